@@ -0,0 +1,384 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+	. "wordfeud/game"
+
+	"golang.org/x/text/language"
+)
+
+// the json api of the http server
+//
+//	POST /api/v1/games                 create a game - the tokens of the human players are returned
+//	GET  /api/v1/games/{id}            the state of the game as seen by the caller
+//	GET  /api/v1/games/{id}/moves      the moves of the game
+//	POST /api/v1/games/{id}/moves      a move, exchange or pass of the caller - the bots move after it
+//	POST /api/v1/games/{id}/validate   the score and the words of a move of the caller without playing it
+//	POST /api/v1/games/{id}/resign     the caller resigns
+//
+// the caller is identified by the token of a player as "Authorization: Bearer token" or ?token=
+// every response is an ApiResult - with the error envelope set when the request failed
+
+const (
+	apiPlayersMax   = 4
+	apiGamesMax     = 1000           // the games kept by the server
+	apiGameIdleTime = 24 * time.Hour // a game which has not been accessed for this long is removed
+	apiRequestMax   = 1 << 16        // the max size in bytes of the body of a request
+)
+
+type apiGame struct {
+	sync.Mutex
+	id       string
+	game     Game
+	owner    uint64 // the id of the signed in user who created the game - 0 for a game created by the api
+	created  time.Time
+	accessed time.Time           // guarded by the lock of apiGames
+	tokens   map[string]PlayerNo // the human player of each token
+}
+
+type apiGames struct {
+	sync.Mutex
+	games map[string]*apiGame
+}
+
+type apiPlayerRequest struct {
+	Name string `json:"name"`
+	Bot  string `json:"bot"` // the level of a bot player - empty for a human player
+}
+
+type apiCreateRequest struct {
+	Language   string             `json:"language"`
+	Dictionary string             `json:"dictionary"`
+	Ruleset    string             `json:"ruleset"` // must be the ruleset of the language when given
+	Seed       uint64             `json:"seed"`
+	Players    []apiPlayerRequest `json:"players"`
+}
+
+type apiMoveRequest struct {
+	Kind        string `json:"kind"` // move (default), exchange or pass
+	Row         int    `json:"row"`
+	Column      int    `json:"column"`
+	Orientation string `json:"orientation"`
+	Word        string `json:"word"`
+	Tiles       string `json:"tiles"`
+}
+
+var errGameNotFound = errors.New("no such game")
+var errTooManyGames = fmt.Errorf("the server has %d games - try again later", apiGamesMax)
+
+func newApiGames() *apiGames {
+	return &apiGames{games: make(map[string]*apiGame)}
+}
+
+func (games *apiGames) get(id string) (*apiGame, error) {
+	games.Lock()
+	defer games.Unlock()
+	game, found := games.games[id]
+	if !found {
+		return nil, errGameNotFound
+	}
+	game.accessed = time.Now()
+	return game, nil
+}
+
+// add adds game after removing the idle games - an error when the server still has apiGamesMax games
+func (games *apiGames) add(game *apiGame) error {
+	games.Lock()
+	defer games.Unlock()
+	now := time.Now()
+	for id, g := range games.games {
+		if now.Sub(g.accessed) > apiGameIdleTime {
+			delete(games.games, id)
+		}
+	}
+	if len(games.games) >= apiGamesMax {
+		return errTooManyGames
+	}
+	game.accessed = now
+	games.games[game.id] = game
+	return nil
+}
+
+func newApiToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("no random token: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// caller returns the player of the token of req - NoPlayer when there is no token
+func (game *apiGame) caller(req *http.Request) (PlayerNo, error) {
+	token := req.URL.Query().Get("token")
+	if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if token == "" {
+		return NoPlayer, nil
+	}
+	playerNo, found := game.tokens[token]
+	if !found {
+		return NoPlayer, fmt.Errorf("the token is not a token of a player of game %s", game.id)
+	}
+	return playerNo, nil
+}
+
+func writeApiResult(w http.ResponseWriter, result *ApiResult, status int) {
+	if result.Error != nil {
+		status = result.Error.Status
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+// apiNotFoundWWW answers the requests of the api which match no endpoint
+func apiNotFoundWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	result := new(ApiResult)
+	writeApiResult(w, result.fail(http.StatusNotFound, "not_found", fmt.Errorf("no api endpoint %s %s", req.Method, req.URL.Path)), 0)
+}
+
+// apiCreateGameWWW answers POST /api/v1/games
+func apiCreateGameWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	result := new(ApiResult)
+	var request apiCreateRequest
+	if err := decodeApiRequest(w, req, &request); err != nil {
+		writeApiResult(w, result.fail(http.StatusBadRequest, "bad_request", fmt.Errorf("invalid game request : %v", err)), 0)
+		return
+	}
+	_, result = createApiGame(server, options, &request, 0)
+	writeApiResult(w, result, http.StatusCreated)
+}
+
+// createApiGame creates the game of request for the signed in user owner (0 for none) and plays
+// the bots until it is the turn of a human player - the game is nil when the result is an error
+// - the server keeps apiGamesMax games and removes the games idle for apiGameIdleTime
+func createApiGame(server *Server, options *GameOptions, request *apiCreateRequest, owner uint64) (*apiGame, *ApiResult) {
+	result := new(ApiResult)
+	options = options.Copy()
+	options.WriteFile = false
+	options.Out = io.Discard
+	options.Dictionary = request.Dictionary
+	if request.Language != "" {
+		tag, err := language.Parse(request.Language)
+		if err != nil || !SupportedLanguage(tag) {
+			return nil, result.fail(http.StatusBadRequest, "unsupported_language", fmt.Errorf("unsupported language \"%s\"", request.Language))
+		}
+		options.Language = tag
+	}
+	options.RandSeed = request.Seed
+	if options.RandSeed == 0 {
+		options.RandSeed = uint64(time.Now().UnixNano())
+	}
+	options.Rand = mathrand.New(mathrand.NewSource(int64(options.RandSeed)))
+
+	if len(request.Players) == 0 {
+		request.Players = []apiPlayerRequest{{Name: "player"}, {Bot: BOT_LEVEL_EXPERT.String()}}
+	}
+	if len(request.Players) < 2 || len(request.Players) > apiPlayersMax {
+		return nil, result.fail(http.StatusBadRequest, "bad_request", fmt.Errorf("a game has 2 to %d players - not %d", apiPlayersMax, len(request.Players)))
+	}
+	players := make(Players, len(request.Players))
+	bots := PlayerNo(0)
+	humans := 0
+	for i, p := range request.Players {
+		if p.Bot == "" {
+			humans++
+			name := p.Name
+			if name == "" {
+				name = fmt.Sprintf("player %d", i+1)
+			}
+			players[i] = HumanPlayer(name)
+			continue
+		}
+		level, err := ParseBotLevel(p.Bot)
+		if err != nil {
+			return nil, result.fail(http.StatusBadRequest, "bad_request", err)
+		}
+		bots++
+		players[i] = LevelBotPlayer(bots, level)
+	}
+	if humans == 0 {
+		// the bots play in response to the moves of the human players only
+		return nil, result.fail(http.StatusBadRequest, "bad_request", fmt.Errorf("a game needs a human player"))
+	}
+
+	game, err := NewGame(options, 1, players)
+	if err != nil {
+		return nil, result.fail(http.StatusBadRequest, "bad_request", err)
+	}
+	if request.Ruleset != "" && request.Ruleset != game.Header().Ruleset {
+		return nil, result.fail(http.StatusBadRequest, "unknown_ruleset",
+			fmt.Errorf("unknown ruleset \"%s\" - the ruleset of %s is %s", request.Ruleset, options.Language.String(), game.Header().Ruleset))
+	}
+	g := &apiGame{id: newApiToken(8), game: game, owner: owner, created: time.Now(), tokens: make(map[string]PlayerNo)}
+	viewer := NoPlayer
+	for i, player := range players {
+		if player.IsHuman() {
+			token := newApiToken(16)
+			g.tokens[token] = PlayerNo(i + 1)
+			result.Tokens = append(result.Tokens, ApiPlayerToken{Player: PlayerNo(i + 1), Token: token})
+			if viewer == NoPlayer {
+				viewer = PlayerNo(i + 1)
+			}
+		}
+	}
+	if err := server.games.add(g); err != nil {
+		return nil, result.fail(http.StatusServiceUnavailable, "too_many_games", err)
+	}
+	g.Lock()
+	defer g.Unlock()
+	game.PlayBots()
+
+	result.GameId = g.id
+	result.Game = game.View(viewer)
+	// only the creator learns the seed - it reveals the racks and the bag
+	result.Game.Seed = options.RandSeed
+	fmt.Fprintf(result.logger(), "game %s created\n", g.id)
+	return g, result.result()
+}
+
+// apiGameWWW answers GET /api/v1/games/{id}
+func apiGameWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	writeApiResult(w, apiGameRequest(server, req, false, func(g *apiGame, playerNo PlayerNo, result *ApiResult) *ApiResult {
+		result.Game = g.game.View(playerNo)
+		return result.result()
+	}), http.StatusOK)
+}
+
+// apiMovesWWW answers GET /api/v1/games/{id}/moves
+func apiMovesWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	writeApiResult(w, apiGameRequest(server, req, false, func(g *apiGame, playerNo PlayerNo, result *ApiResult) *ApiResult {
+		result.Moves = g.game.Moves()
+		return result.result()
+	}), http.StatusOK)
+}
+
+// apiMoveWWW answers POST /api/v1/games/{id}/moves
+func apiMoveWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	writeApiResult(w, apiGameRequest(server, req, true, func(g *apiGame, playerNo PlayerNo, result *ApiResult) *ApiResult {
+		action, err := parseApiAction(w, req, true)
+		if err != nil {
+			return result.fail(http.StatusBadRequest, "bad_request", err)
+		}
+		return apiPlay(g, playerNo, action, result)
+	}), http.StatusOK)
+}
+
+// apiValidateWWW answers POST /api/v1/games/{id}/validate with the score and the words of a move
+// of the caller without playing it
+func apiValidateWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	writeApiResult(w, apiGameRequest(server, req, true, func(g *apiGame, playerNo PlayerNo, result *ApiResult) *ApiResult {
+		action, err := parseApiAction(w, req, false)
+		if err != nil {
+			return result.fail(http.StatusBadRequest, "bad_request", err)
+		}
+		if err := apiCheckTurn(g, playerNo); err != nil {
+			return result.fail(http.StatusConflict, err.code, err)
+		}
+		if result.Preview, err = g.game.PreviewAction(playerNo, action); err != nil {
+			return result.fail(http.StatusUnprocessableEntity, "invalid_move", err)
+		}
+		return result.result()
+	}), http.StatusOK)
+}
+
+// decodeApiRequest decodes the json body of req into request - a body of more than apiRequestMax bytes is an error
+func decodeApiRequest(w http.ResponseWriter, req *http.Request, request any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, req.Body, apiRequestMax)).Decode(request)
+}
+
+// parseApiAction returns the action of the move request of req - only a move placing tiles unless kinds is true
+func parseApiAction(w http.ResponseWriter, req *http.Request, kinds bool) (Action, error) {
+	var request apiMoveRequest
+	if err := decodeApiRequest(w, req, &request); err != nil {
+		return Action{}, fmt.Errorf("invalid move request : %v", err)
+	}
+	action := Action{Row: request.Row, Column: request.Column, Word: request.Word, Tiles: request.Tiles}
+	var err error
+	if request.Kind != "" {
+		if action.Kind, err = ParseMoveKind(request.Kind); err != nil || action.Kind == MOVE_RESIGN || (!kinds && action.Kind != MOVE_PLACE) {
+			if kinds {
+				return action, fmt.Errorf("unknown move kind \"%s\" - expected move, exchange or pass", request.Kind)
+			}
+			return action, fmt.Errorf("only a move placing tiles can be validated - not \"%s\"", request.Kind)
+		}
+	}
+	if action.Kind == MOVE_PLACE {
+		if action.Orientation, err = ParseOrientation(request.Orientation); err != nil {
+			return action, err
+		}
+	}
+	return action, nil
+}
+
+// apiResignWWW answers POST /api/v1/games/{id}/resign
+func apiResignWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	writeApiResult(w, apiGameRequest(server, req, true, func(g *apiGame, playerNo PlayerNo, result *ApiResult) *ApiResult {
+		return apiPlay(g, playerNo, Action{Kind: MOVE_RESIGN}, result)
+	}), http.StatusOK)
+}
+
+func apiPlay(g *apiGame, playerNo PlayerNo, action Action, result *ApiResult) *ApiResult {
+	game := g.game
+	if err := apiCheckTurn(g, playerNo); err != nil {
+		return result.fail(http.StatusConflict, err.code, err)
+	}
+	seqno := len(game.Moves())
+	if err := game.PlayAction(playerNo, action); err != nil {
+		return result.fail(http.StatusUnprocessableEntity, "invalid_move", err)
+	}
+	game.PlayBots()
+	result.Game = game.View(playerNo)
+	result.Moves = game.Moves()[seqno:]
+	return result.result()
+}
+
+type apiTurnError struct {
+	code string
+	error
+}
+
+// apiCheckTurn returns an error when it is not the turn of player playerNo of g
+func apiCheckTurn(g *apiGame, playerNo PlayerNo) *apiTurnError {
+	if g.game.Ended() {
+		return &apiTurnError{"game_ended", fmt.Errorf("game %s has ended", g.id)}
+	}
+	if next := g.game.NextPlayer(); next != playerNo {
+		return &apiTurnError{"not_your_turn", fmt.Errorf("it is the turn of player %d", next)}
+	}
+	return nil
+}
+
+// apiGameRequest finds the game of req and the calling player and calls f holding the lock of the game
+// - when player is true the caller must be a player of the game
+func apiGameRequest(server *Server, req *http.Request, player bool, f func(*apiGame, PlayerNo, *ApiResult) *ApiResult) *ApiResult {
+	result := new(ApiResult)
+	g, err := server.games.get(req.PathValue("id"))
+	if err != nil {
+		return result.fail(http.StatusNotFound, "not_found", fmt.Errorf("%v \"%s\"", err, req.PathValue("id")))
+	}
+	g.Lock()
+	defer g.Unlock()
+	playerNo, err := g.caller(req)
+	if err != nil {
+		return result.fail(http.StatusForbidden, "forbidden", err)
+	}
+	if player && playerNo == NoPlayer {
+		return result.fail(http.StatusUnauthorized, "unauthorized", fmt.Errorf("the token of a player of game %s is required", g.id))
+	}
+	result.GameId = g.id
+	return f(g, playerNo, result)
+}
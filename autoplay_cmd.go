@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	. "wordfeud/context"
+	. "wordfeud/corpus"
 	. "wordfeud/game"
 )
 
@@ -14,7 +15,7 @@ func autoplayCmd(options *GameOptions, _ []string) *GameResult {
 	registerGlobalFlags(flag)
 
 	for seqno := 1; seqno <= options.Count; seqno++ {
-		game, err := NewGame(options, seqno, Players{BotPlayer(1), BotPlayer(2)})
+		game, err := NewGame(options, seqno, BotPlayers(options, 2))
 		if err != nil {
 			fmt.Println(result.errors(), err.Error())
 			return result.result()
@@ -22,6 +23,10 @@ func autoplayCmd(options *GameOptions, _ []string) *GameResult {
 		result.Width = int(game.Dimensions().Width)
 		result.Height = int(game.Dimensions().Height)
 		result.LetterScores = game.LetterScores()
+		result.Tiles = make([]string, len(result.LetterScores))
+		for l := range result.Tiles {
+			result.Tiles[l] = Letter(l).String(game.Corpus())
+		}
 		result.Board = game.Board()
 
 		for n := 0; n < 1000; n++ {
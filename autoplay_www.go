@@ -1,37 +1,80 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
+	. "wordfeud/context"
 	. "wordfeud/game"
 	. "wordfeud/localize"
 )
 
 type autoplayData struct {
+	Lang         string
 	Scrabble     string
 	User         string
 	Autoplay     string
 	MainMenu     string
 	AutoplayGame string
+	Players      []autoplayPlayer
 }
 
-func autoplayWWW(server *Server, w http.ResponseWriter, req *http.Request) {
-	userName := ""
+type autoplayPlayer struct {
+	Label  string
+	Field  string
+	Levels []autoplayLevel
+}
+
+type autoplayLevel struct {
+	Value    string
+	Name     string
+	Selected bool
+}
+
+func autoplayWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
 	scrabble := getScrabble(server)
 	lang := scrabble.options.Language
 	data := autoplayData{
+		Lang:         lang.String(),
 		Scrabble:     Localized(lang, "Scrabble"),
-		User:         userName,
+		User:         server.signedInUserName(req),
 		Autoplay:     Localized(lang, "Two robot player game"),
 		MainMenu:     Localized(lang, "Top level menu"),
 		AutoplayGame: Localized(lang, "Play game"),
+		Players:      make([]autoplayPlayer, 2),
+	}
+	for i := range data.Players {
+		no := i + 1
+		current := scrabble.options.BotLevels.Level(no)
+		player := autoplayPlayer{
+			Label:  fmt.Sprintf(Localized(lang, "Level of %s"), BotPlayerNames[i]),
+			Field:  fmt.Sprintf("level%d", no),
+			Levels: make([]autoplayLevel, len(AllBotLevels)),
+		}
+		for j, level := range AllBotLevels {
+			player.Levels[j] = autoplayLevel{Value: level.String(), Name: Localized(lang, level.String()), Selected: level == current}
+		}
+		data.Players[i] = player
 	}
 
 	scrabble.templates.WriteTemplate(w, "autoplay.html", data)
 }
 
-func autoplayGameWWW(server *Server, w http.ResponseWriter, req *http.Request) {
+func autoplayGameWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
 	scrabble := getScrabble(server)
-	game, err := NewGame(scrabble.options, scrabble.seqno, Players{BotPlayer(1), BotPlayer(2)})
+	options = scrabble.options // the games are written to the www directory of scrabble
+	if req.URL.Query().Has("level1") || req.URL.Query().Has("level2") {
+		options = options.Copy()
+		options.BotLevels = make(BotLevels, 2)
+		for i := range options.BotLevels {
+			level, err := ParseBotLevel(req.URL.Query().Get(fmt.Sprintf("level%d", i+1)))
+			if err != nil {
+				scrabble.templates.WriteError(w, err.Error())
+				return
+			}
+			options.BotLevels[i] = level
+		}
+	}
+	game, err := NewGame(options, scrabble.seqno, BotPlayers(options, 2))
 	if err != nil {
 		scrabble.templates.WriteError(w, err.Error())
 		return
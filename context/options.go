@@ -31,6 +31,9 @@ type GameOptions struct {
 	File       string
 	Directory  string
 	FileFormat FileFormat
+	Lexicon    Lexicon
+	BotLevels  BotLevels // the level of each bot player - the last level is used for the remaining players
+	Dictionary string    // the word lists making up the dictionary - empty for the language corpus file
 	Cmd        string
 	Args       []string
 }
@@ -54,8 +57,7 @@ func (format FileFormat) Extension() string {
 		return ".txt"
 	case FILE_FORMAT_JSON:
 		return ".json"
-	case FILE_FORMAT_HTML:
-	case FILE_FORMAT_WWW:
+	case FILE_FORMAT_HTML, FILE_FORMAT_WWW:
 		return ""
 	}
 	panic(fmt.Sprintf("illegal FileFormat %d (FileFormat.Extension)", format))
@@ -97,6 +99,110 @@ func (format FileFormat) String() string {
 
 }
 
+// Lexicon selects the lexicon structure used for move generation
+type Lexicon byte
+
+const (
+	LEXICON_DAWG   = Lexicon(0)
+	LEXICON_GADDAG = Lexicon(1)
+)
+
+func ParseLexicon(lexiconSpec string) (Lexicon, error) {
+	switch strings.ToLower(lexiconSpec) {
+	case "", "dawg":
+		return LEXICON_DAWG, nil
+	case "gaddag":
+		return LEXICON_GADDAG, nil
+	}
+	return LEXICON_DAWG, fmt.Errorf("unknown lexicon \"%s\" - expected dawg or gaddag", lexiconSpec)
+}
+
+func (lexicon Lexicon) String() string {
+	switch lexicon {
+	case LEXICON_DAWG:
+		return "dawg"
+	case LEXICON_GADDAG:
+		return "gaddag"
+	}
+	panic(fmt.Sprintf("illegal Lexicon %d (Lexicon.String)", lexicon))
+}
+
+// BotLevel is the playing strength of a bot player
+type BotLevel byte
+type BotLevels []BotLevel
+
+const (
+	BOT_LEVEL_EXPERT   = BotLevel(0)
+	BOT_LEVEL_STRONG   = BotLevel(1)
+	BOT_LEVEL_CASUAL   = BotLevel(2)
+	BOT_LEVEL_BEGINNER = BotLevel(3)
+)
+
+var AllBotLevels = BotLevels{BOT_LEVEL_BEGINNER, BOT_LEVEL_CASUAL, BOT_LEVEL_STRONG, BOT_LEVEL_EXPERT}
+
+func ParseBotLevel(levelSpec string) (BotLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(levelSpec)) {
+	case "", "expert":
+		return BOT_LEVEL_EXPERT, nil
+	case "strong":
+		return BOT_LEVEL_STRONG, nil
+	case "casual":
+		return BOT_LEVEL_CASUAL, nil
+	case "beginner":
+		return BOT_LEVEL_BEGINNER, nil
+	}
+	return BOT_LEVEL_EXPERT, fmt.Errorf("unknown bot level \"%s\" - expected beginner, casual, strong or expert", levelSpec)
+}
+
+// ParseBotLevels parses a comma separated list of bot levels - one for each player
+func ParseBotLevels(levelsSpec string) (BotLevels, error) {
+	levels := make(BotLevels, 0, 2)
+	if levelsSpec == "" {
+		return levels, nil
+	}
+	for _, levelSpec := range strings.Split(levelsSpec, ",") {
+		level, err := ParseBotLevel(levelSpec)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+func (level BotLevel) String() string {
+	switch level {
+	case BOT_LEVEL_EXPERT:
+		return "expert"
+	case BOT_LEVEL_STRONG:
+		return "strong"
+	case BOT_LEVEL_CASUAL:
+		return "casual"
+	case BOT_LEVEL_BEGINNER:
+		return "beginner"
+	}
+	panic(fmt.Sprintf("illegal BotLevel %d (BotLevel.String)", level))
+}
+
+// Level returns the level of player no (1..) - expert when no levels are given
+func (levels BotLevels) Level(no int) BotLevel {
+	if len(levels) == 0 {
+		return BOT_LEVEL_EXPERT
+	}
+	if no > len(levels) {
+		return levels[len(levels)-1]
+	}
+	return levels[no-1]
+}
+
+func (levels BotLevels) String() string {
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = level.String()
+	}
+	return strings.Join(names, ",")
+}
+
 func (options *GameOptions) Print(args ...string) {
 	options.Fprint(os.Stdout, args...)
 }
@@ -119,6 +225,9 @@ func (options *GameOptions) Copy() *GameOptions {
 		File:       options.File,
 		Directory:  options.Directory,
 		FileFormat: options.FileFormat,
+		Lexicon:    options.Lexicon,
+		BotLevels:  slices.Clone(options.BotLevels),
+		Dictionary: options.Dictionary,
 		Cmd:        options.Cmd,
 		Args:       args,
 	}
@@ -146,4 +255,7 @@ func (options *GameOptions) Fprint(f io.Writer, args ...string) {
 	fmt.Fprintf(f, "%s   directory:   %s\n", indent, options.Directory)
 	fmt.Fprintf(f, "%s   file:        %s\n", indent, options.File)
 	fmt.Fprintf(f, "%s   fileFormat:  %s\n", indent, options.FileFormat.String())
+	fmt.Fprintf(f, "%s   lexicon:     %s\n", indent, options.Lexicon.String())
+	fmt.Fprintf(f, "%s   botLevels:   %s\n", indent, options.BotLevels.String())
+	fmt.Fprintf(f, "%s   dictionary:  %s\n", indent, options.Dictionary)
 }
@@ -2,15 +2,18 @@ package corpus
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"os"
+	"iter"
+	"math/bits"
 	"regexp"
 	"slices"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
-	lru "github.com/hashicorp/golang-lru"
 	"golang.org/x/text/language"
 )
 
@@ -20,28 +23,37 @@ type Words []Word
 
 const NoLetter = Letter(0)
 
-type Alphabet []rune
+// Alphabet holds the letters of a language in upper case - a letter is the string on a tile
+// which may be more than one character (a digraph like the Spanish CH)
+type Alphabet []string
 
-const AlphabetMax = byte(32) // max 32 (0..31) letters in alphabet
+const AlphabetMax = byte(64) // max 64 (0..63) letters in alphabet - letter 0 is NoLetter
 // OBS if AlphabetMax is changed, so must the definition below of LetterSet
-type LetterSet uint32 // set of Letter - i.e. bitset of 0..31
+type LetterSet uint64 // set of Letter - i.e. bitset of 0..63
 const NullLetterSet = LetterSet(0)
 const AllLetterSet = LetterSet(^NullLetterSet)
 
-type corpusKey struct {
-	fileName string
-}
+type Checksum [sha256.Size]byte
 
 type CorpusStat struct {
 	WordCount      int
 	MinWordLength  int
 	MaxWordLength  int
 	TotalWordsSize int
+	Filtered       []FilterCount // the number of words removed by each filter rule
+	LengthCounts   []int         // the number of words of each length - indexed by length
+	LetterCounts   []int         // the number of occurrences of each letter - indexed by Letter
+	BigramCounts   []int         // the number of occurrences of each pair of letters - indexed by BigramIndex
 }
 
+// BINGO_LENGTHS are the lengths of the words which can be played using all the tiles of the rack -
+// alone or with a letter on the board
+var BINGO_LENGTHS = []int{7, 8}
+
 type Corpus interface {
-	RuneToLetter(rune) Letter
-	LetterToRune(Letter) rune
+	StringToLetter(string) Letter
+	LetterToString(Letter) string
+	ParseLetter(string) (Letter, int)
 	Language() language.Tag
 	NewContent(content io.Reader) (CorpusContent, error)
 	GetFileContent(string) (CorpusContent, error)
@@ -49,7 +61,9 @@ type Corpus interface {
 	FirstLetter() Letter
 	LastLetter() Letter
 	LetterMax() int
+	MinWordLength() int
 	AllLetters() LetterSet
+	ParseWord(string) (Word, error)
 }
 
 type CorpusContent interface {
@@ -59,47 +73,37 @@ type CorpusContent interface {
 	GetWord(i int) Word
 	FindWord(word Word) (wordIndex int, found bool)
 	Stat() CorpusStat
+	FileName() string
+	Checksum() Checksum
+	Sources() []string
+	Provenance(word Word) []string
+	Metadata(word Word) (WordMetadata, bool)
 }
 
 type corpusData struct {
-	key           corpusKey
 	language      language.Tag
 	alphabet      Alphabet
 	allLetters    LetterSet
-	letterRune    []rune
+	letterString  []string
 	letterMax     Letter
 	firstLetter   Letter
 	lastLetter    Letter
-	runeLetter    map[rune]Letter
+	stringLetter  map[string]Letter
+	maxTileLength int // the number of characters of the longest letter
 	minWordLength int
+	filters       []*wordFilter
 }
 
 type corpusContent struct {
 	corpus        Corpus
+	fileName      string
 	words         Words
 	maxWordLength int
 	stat          *CorpusStat
-}
-
-var corpusCache *lru.Cache
-
-func getCachedCorpus(fileName string) *corpusData {
-	var corpus *corpusData
-	if corpusCache == nil {
-		corpusCache, _ = lru.New(10)
-	}
-	cached, found := corpusCache.Get(fileName)
-	if found {
-		corpus = cached.(*corpusData)
-	}
-	return corpus
-}
-
-func setCachedCorpus(corpus *corpusData) {
-	if corpusCache == nil {
-		corpusCache, _ = lru.New(10)
-	}
-	corpusCache.Add(corpus.key, corpus)
+	checksum      Checksum
+	sources       []string    // the names of the word lists the words came from
+	provenance    []SourceSet // the sources of each word - nil when all words came from every source
+	metadata      Metadata    // the metadata of the words - nil when there is none
 }
 
 func NewCorpus(lang language.Tag) (Corpus, error) {
@@ -107,19 +111,27 @@ func NewCorpus(lang language.Tag) (Corpus, error) {
 	corpus := new(corpusData)
 	corpus.language = lang
 	corpus.alphabet = GetLanguageAlphabet(lang)
-	corpus.letterRune = make([]rune, len(corpus.alphabet)+1)
-	corpus.runeLetter = make(map[rune]Letter)
+	corpus.letterString = make([]string, len(corpus.alphabet)+1)
+	corpus.stringLetter = make(map[string]Letter)
 	corpus.minWordLength = 2 // scrabble rules : words may not be one letter words
 	var n Letter = 0
-	for _, r := range corpus.alphabet {
+	for _, s := range corpus.alphabet {
 		n++
 		if n >= Letter(AlphabetMax) {
-			return nil, fmt.Errorf("the alphabet specified has more than %v characters", AlphabetMax)
+			return nil, fmt.Errorf("the alphabet specified has more than %v letters", AlphabetMax-1)
 		}
-		corpus.letterRune[n] = r
-		corpus.runeLetter[r] = n
+		corpus.letterString[n] = s
+		corpus.stringLetter[s] = n
+		corpus.maxTileLength = max(corpus.maxTileLength, utf8.RuneCountInString(s))
 		corpus.allLetters.Set(n)
 	}
+	for _, rule := range GetLanguageFilters(lang) {
+		filter, err := newWordFilter(rule)
+		if err != nil {
+			return nil, err
+		}
+		corpus.filters = append(corpus.filters, filter)
+	}
 	if n > 0 {
 		corpus.firstLetter = 1
 		corpus.lastLetter = n
@@ -129,36 +141,104 @@ func NewCorpus(lang language.Tag) (Corpus, error) {
 }
 
 func (corpus *corpusData) NewContent(content io.Reader) (CorpusContent, error) {
-	var err error
+	words, filtered, err := corpus.scanWords(content)
+	corpusContent := corpus.newWordsContent(words)
+	corpusContent.stat.Filtered = filtered
+	return corpusContent, err
+}
+
+// newWordsContent makes content of the sorted words
+func (corpus *corpusData) newWordsContent(words Words) *corpusContent {
 	corpusContent := new(corpusContent)
 	corpusContent.corpus = corpus
 	corpusContent.stat = new(CorpusStat)
-	corpusContent.words, err = corpus.scanWords(content)
+	corpusContent.words = words
+	stat := corpusContent.stat
+	letterMax := int(corpus.letterMax)
+	stat.LetterCounts = make([]int, letterMax)
+	stat.BigramCounts = make([]int, letterMax*letterMax)
 	for _, w := range corpusContent.words {
 		wordLength := len(w)
 		if wordLength > corpusContent.maxWordLength {
 			corpusContent.maxWordLength = wordLength
 		}
-		corpusContent.stat.TotalWordsSize += wordLength
+		if stat.MinWordLength == 0 || wordLength < stat.MinWordLength {
+			stat.MinWordLength = wordLength
+		}
+		stat.TotalWordsSize += wordLength
+		for wordLength >= len(stat.LengthCounts) {
+			stat.LengthCounts = append(stat.LengthCounts, 0)
+		}
+		stat.LengthCounts[wordLength]++
+		for i, l := range w {
+			stat.LetterCounts[l]++
+			if i > 0 {
+				stat.BigramCounts[BigramIndex(corpus, w[i-1], l)]++
+			}
+		}
 	}
-	corpusContent.stat.WordCount = len(corpusContent.words)
-	corpusContent.stat.MinWordLength = corpus.minWordLength
-	corpusContent.stat.MaxWordLength = corpusContent.maxWordLength
-	return corpusContent, err
+	stat.WordCount = len(corpusContent.words)
+	if stat.WordCount == 0 {
+		stat.MinWordLength = corpus.minWordLength
+	}
+	stat.MaxWordLength = corpusContent.maxWordLength
+	corpusContent.checksum = corpusContent.calcChecksum()
+	return corpusContent
 }
 
-func (corpus *corpusData) scanWords(f io.Reader) (Words, error) {
-	words := make(Words, 0, 10000)
-	var sb strings.Builder
-	sb.WriteString("^[")
-	for _, l := range corpus.letterRune {
-		sb.WriteRune(l)
+// BigramIndex returns the index in CorpusStat.BigramCounts of the letter first followed by second
+func BigramIndex(corpus Corpus, first Letter, second Letter) int {
+	return int(first)*corpus.LetterMax() + int(second)
+}
+
+// LengthCount returns the number of words of length
+func (stat CorpusStat) LengthCount(length int) int {
+	if length < 0 || length >= len(stat.LengthCounts) {
+		return 0
+	}
+	return stat.LengthCounts[length]
+}
+
+// BingoCount returns the number of words of the BINGO_LENGTHS
+func (stat CorpusStat) BingoCount() int {
+	count := 0
+	for _, length := range BINGO_LENGTHS {
+		count += stat.LengthCount(length)
+	}
+	return count
+}
+
+// the checksum is calculated from the sorted words as strings
+// so it does not depend on the letter numbering of the alphabet
+func (content *corpusContent) calcChecksum() Checksum {
+	var checksum Checksum
+	h := sha256.New()
+	for _, w := range content.words {
+		h.Write([]byte(w.String(content.corpus)))
+		h.Write([]byte{'\n'})
 	}
-	sb.WriteString("]+$")
-	ptn := sb.String()
-	r, err := regexp.Compile(ptn)
+	copy(checksum[:], h.Sum(nil))
+	return checksum
+}
+
+// wordRegexp matches the upper case words made of letters of the alphabet
+func (corpus *corpusData) wordRegexp() (*regexp.Regexp, error) {
+	letters := slices.Clone(corpus.alphabet)
+	slices.SortStableFunc(letters, func(l string, r string) int { return len(r) - len(l) })
+	for i, l := range letters {
+		letters[i] = regexp.QuoteMeta(l)
+	}
+	return regexp.Compile("^(?:" + strings.Join(letters, "|") + ")+$")
+}
+
+// scanWords returns the sorted words of f kept by the filter rules and the number of words removed by each rule
+func (corpus *corpusData) scanWords(f io.Reader) (Words, []FilterCount, error) {
+	words := make(Words, 0, 10000)
+	filtered := corpus.filterCounts()
+	filters := corpus.wordFilters()
+	r, err := corpus.wordRegexp()
 	if err != nil {
-		return Words{}, err
+		return Words{}, filtered, err
 	}
 
 	s := bufio.NewScanner(f)
@@ -167,6 +247,9 @@ func (corpus *corpusData) scanWords(f io.Reader) (Words, error) {
 	for s.Scan() {
 		line := strings.ToUpper(s.Text())
 		if !r.MatchString(line) {
+			if line != "" {
+				filtered[FILTER_ALPHABET].Count++
+			}
 			continue
 		}
 		if allWords[line] {
@@ -175,21 +258,27 @@ func (corpus *corpusData) scanWords(f io.Reader) (Words, error) {
 		allWords[line] = true
 
 		word := corpus.stringToWord(line)
-		if len(word) >= corpus.minWordLength {
-			words = append(words, word)
+		if len(word) < corpus.minWordLength {
+			filtered[FILTER_MIN_LENGTH].Count++
+			continue
 		}
+		if i := filterWord(filters, line, len(word)); i >= 0 {
+			filtered[i].Count++
+			continue
+		}
+		words = append(words, word)
 	}
 
 	sort.Slice(words, func(i int, j int) bool {
 		return slices.Compare(words[i], words[j]) < 0
 	})
 
-	return words, nil
+	return words, filtered, nil
 }
 
 func (corpus *corpusData) GetFileContent(fileName string) (CorpusContent, error) {
 	var content CorpusContent
-	f, err := os.Open(fileName)
+	f, err := OpenCorpusFile(fileName)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +287,11 @@ func (corpus *corpusData) GetFileContent(fileName string) (CorpusContent, error)
 	if err != nil {
 		return nil, err
 	}
+	content.(*corpusContent).fileName = fileName
+	content.(*corpusContent).sources = []string{fileName}
+	if content.(*corpusContent).metadata, err = ReadMetadataFile(MetadataFileName(fileName)); err != nil {
+		return nil, err
+	}
 	return content, nil
 }
 
@@ -218,6 +312,40 @@ func (content *corpusContent) Stat() CorpusStat {
 	return *content.stat
 }
 
+func (content *corpusContent) FileName() string {
+	return content.fileName
+}
+
+func (content *corpusContent) Checksum() Checksum {
+	return content.checksum
+}
+
+func (content *corpusContent) Sources() []string {
+	return content.sources
+}
+
+// Provenance returns the names of the sources holding word - nil when word is not in content
+func (content *corpusContent) Provenance(word Word) []string {
+	i, found := content.FindWord(word)
+	if !found {
+		return nil
+	}
+	if content.provenance == nil {
+		return content.sources
+	}
+	return content.provenance[i].Names(content.sources)
+}
+
+// Metadata returns the metadata of word - false when there is none
+func (content *corpusContent) Metadata(word Word) (WordMetadata, bool) {
+	metadata, found := content.metadata[word.String(content.corpus)]
+	return metadata, found
+}
+
+func (checksum Checksum) String() string {
+	return hex.EncodeToString(checksum[:])
+}
+
 func (content *corpusContent) WordCount() int {
 	return content.stat.WordCount
 }
@@ -239,7 +367,7 @@ func (corpus *corpusData) FirstLetter() Letter {
 }
 
 func (corpus *corpusData) LastLetter() Letter {
-	return corpus.firstLetter
+	return corpus.lastLetter
 }
 
 func (corpus *corpusData) AllLetters() LetterSet {
@@ -250,15 +378,32 @@ func (corpus *corpusData) Language() language.Tag {
 	return corpus.language
 }
 
-func (corpus *corpusData) RuneToLetter(r rune) Letter {
-	return corpus.runeLetter[r]
+func (corpus *corpusData) StringToLetter(s string) Letter {
+	return corpus.stringLetter[s]
 }
 
-func (corpus *corpusData) LetterToRune(letter Letter) rune {
-	if letter < 1 || letter > corpus.letterMax {
-		return 0
+func (corpus *corpusData) LetterToString(letter Letter) string {
+	if letter < 1 || letter >= corpus.letterMax {
+		return ""
 	}
-	return corpus.letterRune[letter]
+	return corpus.letterString[letter]
+}
+
+// ParseLetter returns the longest letter (in any case) str begins with and its length in bytes in str.
+// The letter is NoLetter when str does not begin with a letter of the alphabet.
+func (corpus *corpusData) ParseLetter(str string) (Letter, int) {
+	ends := make([]int, 0, corpus.maxTileLength)
+	for end := 0; len(ends) < corpus.maxTileLength && end < len(str); {
+		_, size := utf8.DecodeRuneInString(str[end:])
+		end += size
+		ends = append(ends, end)
+	}
+	for i := len(ends) - 1; i >= 0; i-- {
+		if l, found := corpus.stringLetter[strings.ToUpper(str[:ends[i]])]; found {
+			return l, ends[i]
+		}
+	}
+	return NoLetter, 0
 }
 
 func (content *corpusContent) GetWord(i int) Word {
@@ -278,15 +423,27 @@ func (lhs Word) Equal(rhs Word) bool {
 }
 
 func (corpus *corpusData) stringToWord(str string) Word {
-	var word Word = make(Word, 0, len(str))
-	for _, r := range str {
-		l, ok := corpus.runeLetter[r]
-		if !ok {
-			return Word{}
+	word, err := corpus.ParseWord(str)
+	if err != nil {
+		return Word{}
+	}
+	return word
+}
+
+// ParseWord converts str (in any case) to a Word of the corpus alphabet.
+// The longest letter is taken first - "CHILLAR" is CH I LL A R when CH and LL are letters.
+func (corpus *corpusData) ParseWord(str string) (Word, error) {
+	word := make(Word, 0, len(str))
+	for i := 0; i < len(str); {
+		l, n := corpus.ParseLetter(str[i:])
+		if l == NoLetter {
+			r, _ := utf8.DecodeRuneInString(str[i:])
+			return nil, fmt.Errorf("'%c' in \"%s\" is not a letter of the %s alphabet", r, str, corpus.language.String())
 		}
 		word = append(word, l)
+		i += n
 	}
-	return word
+	return word, nil
 }
 
 func (word Word) String(corpus Corpus) string {
@@ -295,7 +452,7 @@ func (word Word) String(corpus Corpus) string {
 		if c == 0 {
 			break
 		}
-		str.WriteRune(corpus.LetterToRune(c))
+		str.WriteString(corpus.LetterToString(c))
 	}
 	return str.String()
 }
@@ -314,6 +471,17 @@ func (letterSet *LetterSet) Unset(letter Letter) *LetterSet {
 	return letterSet
 }
 
+// Letters returns the letters of the set in increasing order
+func (letterSet LetterSet) Letters() iter.Seq[Letter] {
+	return func(yield func(Letter) bool) {
+		for set := letterSet; set != 0; set &= set - 1 {
+			if !yield(Letter(bits.TrailingZeros64(uint64(set)))) {
+				return
+			}
+		}
+	}
+}
+
 func (letterSet LetterSet) String(corpus Corpus) string {
 	var s strings.Builder
 	var first = true
@@ -325,7 +493,7 @@ func (letterSet LetterSet) String(corpus Corpus) string {
 			} else {
 				s.WriteRune(',')
 			}
-			s.WriteRune(rune(corpus.LetterToRune(l)))
+			s.WriteString(corpus.LetterToString(l))
 		}
 	}
 	s.WriteRune('}')
@@ -333,9 +501,9 @@ func (letterSet LetterSet) String(corpus Corpus) string {
 }
 
 func (letter Letter) String(corpus Corpus) string {
-	rune := corpus.LetterToRune(letter)
-	if rune == 0 {
-		return ""
-	}
-	return string(rune)
+	return corpus.LetterToString(letter)
+}
+
+func (alphabet Alphabet) String() string {
+	return strings.Join(alphabet, "")
 }
@@ -1,8 +1,19 @@
 package corpus
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"golang.org/x/text/language"
 )
@@ -78,3 +89,477 @@ func Test_scanWordsDK(t *testing.T) {
 		results[s] = false
 	}
 }
+
+func Test_LastLetter(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_LastLetter() failed to create corpus : %v", err)
+		return
+	}
+	// a joker is expanded to the letters FirstLetter .. LastLetter - it must be every letter of the alphabet
+	var letters LetterSet
+	for l := corpus.FirstLetter(); l <= corpus.LastLetter(); l++ {
+		letters.Set(l)
+	}
+	missing := make([]string, 0)
+	for l := Letter(1); int(l) < corpus.LetterMax(); l++ {
+		if corpus.AllLetters().Test(l) && !letters.Test(l) {
+			missing = append(missing, corpus.LetterToString(l))
+		}
+	}
+	if len(missing) > 0 {
+		t.Errorf("Test_LastLetter() the letters %v are missing from FirstLetter .. LastLetter", missing)
+	}
+}
+
+func Test_SharedFileContent(t *testing.T) {
+	corpus, err := SharedCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_SharedFileContent - cannot get shared corpus : %v", err)
+		return
+	}
+	other, _ := SharedCorpus(language.Danish)
+	if other != corpus {
+		t.Errorf("Test_SharedFileContent - SharedCorpus returned different instances")
+	}
+	const n = 8
+	contents := make(chan CorpusContent, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			content, err := SharedFileContent(corpus, "../data_test/corpus_dk_test.txt")
+			if err != nil {
+				t.Errorf("Test_SharedFileContent - cannot get shared content : %v", err)
+			}
+			contents <- content
+		}()
+	}
+	first := <-contents
+	for i := 1; i < n; i++ {
+		if content := <-contents; content != first {
+			t.Errorf("Test_SharedFileContent - SharedFileContent returned different instances")
+		}
+	}
+	if first == nil || first.WordCount() == 0 {
+		t.Errorf("Test_SharedFileContent - shared content has no words")
+	}
+}
+
+func Test_DictionaryContent(t *testing.T) {
+	// the danish definition with the sources of the test
+	defer LoadLanguages("")
+	directory := t.TempDir()
+	var definition languageFile
+	data, err := builtinLanguages.ReadFile("languages/da.json")
+	if err == nil {
+		err = json.Unmarshal(data, &definition)
+	}
+	if err != nil {
+		t.Fatalf("Test_DictionaryContent - cannot read the danish definition : %v", err)
+	}
+	definition.Sources = map[string]string{"cor": "cor.txt", "ddo": "ddo.txt", "ods": "ods.txt", "proper": "proper.txt"}
+	data, _ = json.Marshal(definition)
+	if err := os.WriteFile(directory+"/da.json", data, 0644); err != nil {
+		t.Fatalf("Test_DictionaryContent - %v", err)
+	}
+	if err := LoadLanguages(directory); err != nil {
+		t.Fatalf("Test_DictionaryContent - %v", err)
+	}
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Fatalf("Test_DictionaryContent - cannot create corpus : %v", err)
+	}
+	sourceWords := map[string]string{
+		"cor":    "abe\nbil\nhus\n",
+		"ddo":    "abe\nbil\nkat\n",
+		"ods":    "bil\nhest\nkat\n",
+		"proper": "hest\n",
+	}
+	getContent := func(fileName string) (CorpusContent, error) {
+		for name, f := range GetLanguageSources(language.Danish) {
+			if f == fileName {
+				return corpus.NewContent(strings.NewReader(sourceWords[name]))
+			}
+		}
+		t.Fatalf("Test_DictionaryContent - unexpected file %s", fileName)
+		return nil, nil
+	}
+	tests := []struct {
+		spec       string
+		words      []string
+		provenance map[string]string
+	}{
+		{"ddo ∪ ods minus proper", []string{"ABE", "BIL", "KAT"}, map[string]string{"ABE": "ddo", "BIL": "ddo+ods", "KAT": "ddo+ods"}},
+		{"cor & ddo", []string{"ABE", "BIL"}, map[string]string{"ABE": "cor+ddo", "BIL": "cor+ddo"}},
+		{"cor+ods-ddo", []string{"HEST", "HUS"}, map[string]string{"HEST": "ods", "HUS": "cor"}},
+	}
+	for _, test := range tests {
+		spec, err := ParseDictionarySpec(test.spec)
+		if err != nil {
+			t.Errorf("Test_DictionaryContent - %s : %v", test.spec, err)
+			continue
+		}
+		content, err := NewDictionaryContent(corpus, spec, getContent)
+		if err != nil {
+			t.Errorf("Test_DictionaryContent - %s : %v", test.spec, err)
+			continue
+		}
+		words := make([]string, 0)
+		for _, w := range content.Words() {
+			words = append(words, w.String(corpus))
+			if provenance := strings.Join(content.Provenance(w), "+"); provenance != test.provenance[w.String(corpus)] {
+				t.Errorf("Test_DictionaryContent - %s : provenance of %s is %s expected %s", test.spec, w.String(corpus), provenance, test.provenance[w.String(corpus)])
+			}
+		}
+		if strings.Join(words, " ") != strings.Join(test.words, " ") {
+			t.Errorf("Test_DictionaryContent - %s : words %v expected %v", test.spec, words, test.words)
+		}
+	}
+	for _, spec := range []string{"", "ddo +", "+ ddo", "ddo ods", "ddo * ods"} {
+		if _, err := ParseDictionarySpec(spec); err == nil {
+			t.Errorf("Test_DictionaryContent - invalid spec \"%s\" accepted", spec)
+		}
+	}
+	spec, _ := ParseDictionarySpec("ddo + nosuch")
+	if _, err := NewDictionaryContent(corpus, spec, getContent); err == nil {
+		t.Errorf("Test_DictionaryContent - unknown source accepted")
+	}
+}
+
+func Test_ImportWords(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Fatalf("Test_ImportWords - cannot create corpus : %v", err)
+	}
+	fileName := t.TempDir() + "/source.tsv"
+	lines := "1\tbil\n2\tÆBLE\n3\tA\u030age\n4\tq-tip\n5\n6\ta\n7\tBil\n8\tåge\n"
+	if err = os.WriteFile(fileName, []byte(lines), 0644); err != nil {
+		t.Fatalf("Test_ImportWords - cannot write source : %v", err)
+	}
+	report, err := ImportWords(corpus, []ImportSource{{FileName: fileName, Column: 2}})
+	if err != nil {
+		t.Fatalf("Test_ImportWords - %v", err)
+	}
+	if words := strings.Join(report.Words, " "); words != "bil åge æble" {
+		t.Errorf("Test_ImportWords - words \"%s\" expected \"bil æble åge\"", words)
+	}
+	if report.Duplicates != 2 {
+		t.Errorf("Test_ImportWords - %d duplicates expected 2", report.Duplicates)
+	}
+	reasons := make([]string, len(report.Rejected))
+	for i, r := range report.Rejected {
+		reasons[i] = r.Reason
+	}
+	if expected := []string{REJECT_ALPHABET, REJECT_MISSING_COLUMN, REJECT_TOO_SHORT}; !slices.Equal(reasons, expected) {
+		t.Errorf("Test_ImportWords - rejected %v expected %v", reasons, expected)
+	}
+}
+
+func Test_FilterRules(t *testing.T) {
+	c, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Fatalf("Test_FilterRules - cannot create corpus : %v", err)
+	}
+	corpus := c.(*corpusData)
+	corpus.filters = nil
+	for _, rule := range []FilterRule{
+		{Kind: FILTER_MAX_LENGTH, Length: 5},
+		{Kind: FILTER_EXCLUDE_LIST, Words: []string{"kat"}},
+		{Kind: FILTER_EXCLUDE_REGEXP, Pattern: "^[^AEIOUYÆØÅ]+$"},
+		{Kind: FILTER_EXCLUDE_POS, POS: []string{"propr."}},
+	} {
+		filter, err := newWordFilter(rule)
+		if err != nil {
+			t.Fatalf("Test_FilterRules - %v", err)
+		}
+		corpus.filters = append(corpus.filters, filter)
+	}
+	content, err := corpus.NewContent(strings.NewReader("abe\nq-tip\na\nkat\nhest\nhm\nbilerne\nhest\n"))
+	if err != nil {
+		t.Fatalf("Test_FilterRules - %v", err)
+	}
+	words := make([]string, 0)
+	for _, w := range content.Words() {
+		words = append(words, w.String(corpus))
+	}
+	if strings.Join(words, " ") != "ABE HEST" {
+		t.Errorf("Test_FilterRules - words %v expected [ABE HEST]", words)
+	}
+	expected := []FilterCount{{"alphabet", 1}, {"min length", 1}, {"max length", 1}, {"exclude list", 1}, {"exclude regexp", 1}}
+	if !slices.Equal(content.Stat().Filtered, expected) {
+		t.Errorf("Test_FilterRules - filtered %v expected %v", content.Stat().Filtered, expected)
+	}
+	if corpus.posFilter("propr.") == nil || corpus.posFilter("sb.") != nil {
+		t.Errorf("Test_FilterRules - part of speech filter does not exclude \"propr.\" only")
+	}
+}
+
+func Test_DiffContents(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Fatalf("Test_DiffContents - cannot create corpus : %v", err)
+	}
+	old, _ := corpus.NewContent(strings.NewReader("abe\nbil\nhus\nkatte\n"))
+	new, _ := corpus.NewContent(strings.NewReader("abe\nbiler\nhus\nkat\nævl\n"))
+	diff := DiffContents(old, new)
+	toStrings := func(words Words) string {
+		s := make([]string, len(words))
+		for i, w := range words {
+			s[i] = w.String(corpus)
+		}
+		return strings.Join(s, " ")
+	}
+	if added := toStrings(diff.Added); added != "BILER KAT ÆVL" {
+		t.Errorf("Test_DiffContents - added \"%s\" expected \"BILER KAT ÆVL\"", added)
+	}
+	if removed := toStrings(diff.Removed); removed != "BIL KATTE" {
+		t.Errorf("Test_DiffContents - removed \"%s\" expected \"BIL KATTE\"", removed)
+	}
+	if diff.AddedByLength[3] != 2 || diff.AddedByLength[5] != 1 || diff.RemovedByLength[3] != 1 || diff.RemovedByLength[5] != 1 {
+		t.Errorf("Test_DiffContents - counts by length %v %v", diff.AddedByLength, diff.RemovedByLength)
+	}
+}
+
+func Test_LoadLanguages(t *testing.T) {
+	defer LoadLanguages("")
+	directory := t.TempDir()
+	faroese := `{"language": "fo", "names": {"en": "Faroese"}, "corpus": "corpus_fo.txt", "blanks": 1,
+		"tiles": [{"letter": "a", "count": 3, "value": 1}, {"letter": "Ð", "count": 1, "value": 5}],
+		"filters": [{"kind": "max length"}, {"kind": "exclude regexp", "pattern": "^Ð"}]}`
+	if err := os.WriteFile(directory+"/fo.json", []byte(faroese), 0644); err != nil {
+		t.Fatalf("Test_LoadLanguages - %v", err)
+	}
+	if err := LoadLanguages(directory); err != nil {
+		t.Fatalf("Test_LoadLanguages - %v", err)
+	}
+	fo := language.Make("fo")
+	if !SupportedLanguage(fo) || !SupportedLanguage(language.Danish) {
+		t.Fatalf("Test_LoadLanguages - supported languages %v expected da and fo", SupportedLanguages())
+	}
+	if name := GetLanguageName(fo, language.English); name != "Faroese" {
+		t.Errorf("Test_LoadLanguages - name %s expected Faroese", name)
+	}
+	if alphabet := GetLanguageAlphabet(fo).String(); alphabet != "AÐ" || GetLanguageBlanks(fo) != 1 || GetLanguageFileName(fo) != "data/corpus_fo.txt" {
+		t.Errorf("Test_LoadLanguages - alphabet %s blanks %d file %s", alphabet, GetLanguageBlanks(fo), GetLanguageFileName(fo))
+	}
+	corpus, err := NewCorpus(fo)
+	if err != nil {
+		t.Fatalf("Test_LoadLanguages - %v", err)
+	}
+	content, _ := corpus.NewContent(strings.NewReader("aa\nðaa\n"))
+	if content.WordCount() != 1 || content.Stat().Filtered[2] != (FilterCount{"max length", 0}) {
+		t.Errorf("Test_LoadLanguages - filtered %v", content.Stat().Filtered)
+	}
+
+	if err := os.WriteFile(directory+"/xx.json", []byte(`{"language": "xx", "corpus": "x.txt", "filters": [{"kind": "no such kind"}]}`), 0644); err != nil {
+		t.Fatalf("Test_LoadLanguages - %v", err)
+	}
+	if err := LoadLanguages(directory); err == nil {
+		t.Errorf("Test_LoadLanguages - invalid definition accepted")
+	}
+}
+
+func Test_DigraphLetters(t *testing.T) {
+	corpus, err := NewCorpus(language.Spanish)
+	if err != nil {
+		t.Fatalf("Test_DigraphLetters - %v", err)
+	}
+	word, err := corpus.ParseWord("chillar")
+	if err != nil || len(word) != 5 || word[0].String(corpus) != "CH" || word[2].String(corpus) != "LL" {
+		t.Errorf("Test_DigraphLetters - CHILLAR parsed to %v %v", word, err)
+	}
+	if s := word.String(corpus); s != "CHILLAR" {
+		t.Errorf("Test_DigraphLetters - CHILLAR is written %s", s)
+	}
+	if _, err := corpus.ParseWord("kilo"); err == nil {
+		t.Errorf("Test_DigraphLetters - K accepted")
+	}
+	content, err := corpus.NewContent(strings.NewReader("perro\ncasa\nwok\ncarro\n"))
+	if err != nil {
+		t.Fatalf("Test_DigraphLetters - %v", err)
+	}
+	words := make([]string, 0)
+	for _, w := range content.Words() {
+		words = append(words, w.String(corpus))
+	}
+	if strings.Join(words, " ") != "CARRO CASA PERRO" || len(content.GetWord(0)) != 4 {
+		t.Errorf("Test_DigraphLetters - words %v", words)
+	}
+}
+
+func Test_LargeAlphabet(t *testing.T) {
+	defer LoadLanguages("")
+	directory := t.TempDir()
+	tiles := make([]string, 0)
+	for _, r := range "ABCDEFGHIJKLMNOPQRSTUVWXYZÀÁÂÃÄÅÆÇÈÉÊËÌÍÎÏ" {
+		tiles = append(tiles, fmt.Sprintf(`{"letter": "%c", "count": 2, "value": 1}`, r))
+	}
+	definition := `{"language": "vi", "corpus": "corpus_vi.txt", "tiles": [` + strings.Join(tiles, ", ") + `]}`
+	if err := os.WriteFile(directory+"/vi.json", []byte(definition), 0644); err != nil {
+		t.Fatalf("Test_LargeAlphabet - %v", err)
+	}
+	if err := LoadLanguages(directory); err != nil {
+		t.Fatalf("Test_LargeAlphabet - %v", err)
+	}
+	corpus, err := NewCorpus(language.Make("vi"))
+	if err != nil {
+		t.Fatalf("Test_LargeAlphabet - %v", err)
+	}
+	if corpus.LastLetter() != 42 {
+		t.Errorf("Test_LargeAlphabet - last letter %d expected 42", corpus.LastLetter())
+	}
+	word, err := corpus.ParseWord("aïa")
+	if err != nil {
+		t.Fatalf("Test_LargeAlphabet - %v", err)
+	}
+	var letters LetterSet
+	letters.Set(word[1]).Set(word[0])
+	if s := slices.Collect(letters.Letters()); !slices.Equal(s, []Letter{1, 42}) || letters.String(corpus) != "{A,Ï}" {
+		t.Errorf("Test_LargeAlphabet - letters %v %s", s, letters.String(corpus))
+	}
+}
+
+func Test_ProposeTiles(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Fatalf("Test_ProposeTiles - %v", err)
+	}
+	content, err := corpus.GetFileContent("../data_test/dk_partial.txt")
+	if err != nil {
+		t.Fatalf("Test_ProposeTiles - %v", err)
+	}
+	proposal, err := ProposeTiles(content, 100, 2)
+	if err != nil {
+		t.Fatalf("Test_ProposeTiles - %v", err)
+	}
+	count := 0
+	tiles := make(map[string]TileStat)
+	for _, tile := range proposal.Tiles {
+		count += tile.Count
+		tiles[tile.Letter] = tile
+		if tile.Count < 1 || tile.Value < TILES_MIN_VALUE || tile.Value > TILES_MAX_VALUE || tile.CurrentCount == 0 {
+			t.Errorf("Test_ProposeTiles - tile %v", tile)
+		}
+	}
+	if count != 98 || len(tiles) != 27 {
+		t.Errorf("Test_ProposeTiles - %d tiles of %d letters expected 98 of 27", count, len(tiles))
+	}
+	if tiles["E"].Count <= tiles["Z"].Count || tiles["E"].Value >= tiles["Z"].Value {
+		t.Errorf("Test_ProposeTiles - E %v Z %v", tiles["E"], tiles["Z"])
+	}
+	data, err := proposal.LanguageDefinition()
+	if err != nil {
+		t.Fatalf("Test_ProposeTiles - %v", err)
+	}
+	def, err := parseLanguageDefinition(data, "da.json")
+	if err != nil {
+		t.Fatalf("Test_ProposeTiles - %v", err)
+	}
+	if len(def.pieces) != 27 || def.pieces[4].character != "E" || int(def.pieces[4].count) != tiles["E"].Count || def.blanks != 2 {
+		t.Errorf("Test_ProposeTiles - definition %v", def.pieces)
+	}
+	if _, err := ProposeTiles(content, 20, 2); err == nil {
+		t.Errorf("Test_ProposeTiles - a bag of 20 tiles accepted")
+	}
+}
+
+func Test_CorpusStat(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Fatalf("Test_CorpusStat - %v", err)
+	}
+	content, err := corpus.NewContent(strings.NewReader("abe\nbad\nabstrakt\nbarberer\nbade\n"))
+	if err != nil {
+		t.Fatalf("Test_CorpusStat - %v", err)
+	}
+	stat := content.Stat()
+	a, b, e := corpus.StringToLetter("A"), corpus.StringToLetter("B"), corpus.StringToLetter("E")
+	if stat.MinWordLength != 3 || stat.LengthCount(3) != 2 || stat.LengthCount(8) != 2 || stat.BingoCount() != 2 {
+		t.Errorf("Test_CorpusStat - lengths %d %v", stat.MinWordLength, stat.LengthCounts)
+	}
+	if stat.LetterCounts[a] != 6 || stat.LetterCounts[e] != 4 {
+		t.Errorf("Test_CorpusStat - letter counts %v", stat.LetterCounts)
+	}
+	if stat.BigramCounts[BigramIndex(corpus, b, a)] != 3 || stat.BigramCounts[BigramIndex(corpus, a, b)] != 2 {
+		t.Errorf("Test_CorpusStat - bigram BA %d AB %d", stat.BigramCounts[BigramIndex(corpus, b, a)], stat.BigramCounts[BigramIndex(corpus, a, b)])
+	}
+}
+
+func Test_WordMetadata(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Fatalf("Test_WordMetadata - cannot create corpus : %v", err)
+	}
+	directory := t.TempDir()
+	lines := "biler\tbil\tsb.\t120\tkøretøj med motor\nbil\tbil\tsb.\t400\t\nhuse\thus\tsb.\t\t\nhuse\thuse\tvb.\t80\tgive husly\n"
+	if err = os.WriteFile(directory+"/source.tsv", []byte(lines), 0644); err != nil {
+		t.Fatalf("Test_WordMetadata - cannot write source : %v", err)
+	}
+	source := ImportSource{FileName: directory + "/source.tsv", Column: 1, POSColumn: 3, LemmaColumn: 2, FrequencyColumn: 4, GlossColumn: 5}
+	report, err := ImportWords(corpus, []ImportSource{source})
+	if err != nil {
+		t.Fatalf("Test_WordMetadata - %v", err)
+	}
+	expected := Metadata{
+		"BIL":   {Lemma: "bil", POS: "sb.", FrequencyRank: 1},
+		"BILER": {Lemma: "bil", POS: "sb.", FrequencyRank: 2, Gloss: "køretøj med motor"},
+		"HUSE":  {Lemma: "hus", POS: "sb.", FrequencyRank: 3},
+	}
+	if !maps.Equal(report.Metadata, expected) {
+		t.Errorf("Test_WordMetadata - metadata %v expected %v", report.Metadata, expected)
+	}
+	fileName := directory + "/corpus.txt"
+	if err = report.WriteCorpusFile(fileName); err != nil {
+		t.Fatalf("Test_WordMetadata - %v", err)
+	}
+	if err = report.WriteMetadataFile(fileName); err != nil {
+		t.Fatalf("Test_WordMetadata - %v", err)
+	}
+	content, err := corpus.GetFileContent(fileName)
+	if err != nil {
+		t.Fatalf("Test_WordMetadata - %v", err)
+	}
+	word, _ := corpus.ParseWord("biler")
+	if metadata, found := content.Metadata(word); !found || metadata != expected["BILER"] {
+		t.Errorf("Test_WordMetadata - BILER metadata %v", metadata)
+	}
+}
+
+func Test_GzipCorpusFile(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_GzipCorpusFile() failed to create corpus : %v", err)
+		return
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte("ABE\nHUS\nHUSE\n"))
+	w.Close()
+	directory := t.TempDir()
+	if err := os.WriteFile(path.Join(directory, "corpus_gz.txt.gz"), buf.Bytes(), 0644); err != nil {
+		t.Errorf("Test_GzipCorpusFile() failed to write file : %v", err)
+		return
+	}
+	defer EmbedCorpusFiles(nil)
+	EmbedCorpusFiles(fstest.MapFS{"corpus_embedded.txt.gz": &fstest.MapFile{Data: buf.Bytes()}})
+
+	for _, fileName := range []string{
+		path.Join(directory, "corpus_gz.txt.gz"),
+		path.Join(directory, "corpus_gz.txt"),
+		path.Join("nowhere", "corpus_embedded.txt"),
+	} {
+		content, err := corpus.GetFileContent(fileName)
+		if err != nil {
+			t.Errorf("Test_GzipCorpusFile() failed to read \"%s\" : %v", fileName, err)
+			continue
+		}
+		if content.WordCount() != 3 {
+			t.Errorf("Test_GzipCorpusFile() \"%s\" has %d words expected 3", fileName, content.WordCount())
+		}
+	}
+	if _, err := corpus.GetFileContent(path.Join(directory, "corpus_missing.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Test_GzipCorpusFile() missing file gave %v", err)
+	}
+	if name := MetadataFileName("data/corpus_dk.txt.gz"); name != "data/corpus_dk.meta" {
+		t.Errorf("Test_GzipCorpusFile() metadata file name %s expected data/corpus_dk.meta", name)
+	}
+}
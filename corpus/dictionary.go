@@ -0,0 +1,219 @@
+package corpus
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+// A dictionary combines the word lists (sources) of a language into one content.
+// The spec names the sources and is evaluated from left to right:
+//
+//	ddo + ods - proper      the words of DDO or ODS which are not proper nouns
+//	cor & ddo               the words found in both cor1.02 and DDO
+//
+// Union is written "+", "|", "∪" or "union", intersection "&", "∩" or "intersect"
+// and difference "-", "−", "\" or "minus".
+type DictionaryOp byte
+
+const (
+	DICTIONARY_UNION        = DictionaryOp(0)
+	DICTIONARY_INTERSECTION = DictionaryOp(1)
+	DICTIONARY_MINUS        = DictionaryOp(2)
+)
+
+type DictionaryTerm struct {
+	Op     DictionaryOp // how the source is combined with the terms before it - the first term is a union
+	Source string
+}
+
+type DictionarySpec []DictionaryTerm
+
+// SourceSet is a bitset of the sources (by index) a word came from
+type SourceSet uint32
+
+const DictionaryMaxSources = 32
+
+var dictionaryOps = map[string]DictionaryOp{
+	"+": DICTIONARY_UNION, "|": DICTIONARY_UNION, "∪": DICTIONARY_UNION, "union": DICTIONARY_UNION,
+	"&": DICTIONARY_INTERSECTION, "∩": DICTIONARY_INTERSECTION, "intersect": DICTIONARY_INTERSECTION,
+	"-": DICTIONARY_MINUS, "−": DICTIONARY_MINUS, "\\": DICTIONARY_MINUS, "minus": DICTIONARY_MINUS,
+}
+
+func (op DictionaryOp) String() string {
+	switch op {
+	case DICTIONARY_UNION:
+		return "+"
+	case DICTIONARY_INTERSECTION:
+		return "&"
+	case DICTIONARY_MINUS:
+		return "-"
+	}
+	panic(fmt.Sprintf("illegal DictionaryOp %d (DictionaryOp.String)", op))
+}
+
+func dictionaryTokens(specStr string) []string {
+	tokens := make([]string, 0)
+	var name strings.Builder
+	flush := func() {
+		if name.Len() > 0 {
+			tokens = append(tokens, name.String())
+			name.Reset()
+		}
+	}
+	for _, r := range specStr {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.':
+			name.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			tokens = append(tokens, string(r))
+		}
+	}
+	flush()
+	return tokens
+}
+
+func ParseDictionarySpec(specStr string) (DictionarySpec, error) {
+	spec := make(DictionarySpec, 0)
+	expectSource := true
+	op := DICTIONARY_UNION
+	for _, token := range dictionaryTokens(specStr) {
+		tokenOp, isOp := dictionaryOps[token]
+		switch {
+		case expectSource && isOp:
+			return nil, fmt.Errorf("dictionary \"%s\" : expected a source name but found \"%s\"", specStr, token)
+		case expectSource:
+			spec = append(spec, DictionaryTerm{Op: op, Source: token})
+		case !isOp:
+			return nil, fmt.Errorf("dictionary \"%s\" : expected +, & or - but found \"%s\"", specStr, token)
+		default:
+			op = tokenOp
+		}
+		expectSource = !expectSource
+	}
+	if len(spec) == 0 || expectSource {
+		return nil, fmt.Errorf("dictionary \"%s\" : expected a source name at the end", specStr)
+	}
+	if len(spec) > DictionaryMaxSources {
+		return nil, fmt.Errorf("dictionary \"%s\" : more than %d sources", specStr, DictionaryMaxSources)
+	}
+	return spec, nil
+}
+
+func (spec DictionarySpec) String() string {
+	var sb strings.Builder
+	for i, term := range spec {
+		if i > 0 {
+			fmt.Fprintf(&sb, " %s ", term.Op.String())
+		}
+		sb.WriteString(term.Source)
+	}
+	return sb.String()
+}
+
+// Names returns the names of sources in the set
+func (set SourceSet) Names(sources []string) []string {
+	names := make([]string, 0, bits.OnesCount32(uint32(set)))
+	for i, name := range sources {
+		if set&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SourceFiles returns the file name of each source of spec
+func (spec DictionarySpec) SourceFiles(lang language.Tag) ([]string, error) {
+	sources := GetLanguageSources(lang)
+	fileNames := make([]string, len(spec))
+	for i, term := range spec {
+		fileName, found := sources[term.Source]
+		if !found {
+			return nil, fmt.Errorf("dictionary \"%s\" : the %s language has no source \"%s\"", spec.String(), lang.String(), term.Source)
+		}
+		fileNames[i] = fileName
+	}
+	return fileNames, nil
+}
+
+// NewDictionaryContent combines the sources of spec read with getContent.
+// The provenance of each word is the union and intersection sources holding it.
+func NewDictionaryContent(corpus Corpus, spec DictionarySpec, getContent func(fileName string) (CorpusContent, error)) (CorpusContent, error) {
+	fileNames, err := spec.SourceFiles(corpus.Language())
+	if err != nil {
+		return nil, err
+	}
+	provenance := make(map[string]SourceSet)
+	words := make(map[string]Word)
+	contents := make([]CorpusContent, len(spec))
+	for i, term := range spec {
+		content, err := getContent(fileNames[i])
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = content
+		source := SourceSet(1 << i)
+		switch term.Op {
+		case DICTIONARY_UNION:
+			for _, w := range content.Words() {
+				words[string(w)] = w
+				provenance[string(w)] |= source
+			}
+		case DICTIONARY_INTERSECTION:
+			for key, w := range words {
+				if _, found := content.FindWord(w); found {
+					provenance[key] |= source
+				} else {
+					delete(words, key)
+				}
+			}
+		case DICTIONARY_MINUS:
+			for key, w := range words {
+				if _, found := content.FindWord(w); found {
+					delete(words, key)
+				}
+			}
+		}
+	}
+	sorted := make(Words, 0, len(words))
+	for _, w := range words {
+		sorted = append(sorted, w)
+	}
+	slices.SortFunc(sorted, slices.Compare)
+	dictionary := corpus.(*corpusData).newWordsContent(sorted)
+	dictionary.sources = make([]string, len(spec))
+	for i, term := range spec {
+		dictionary.sources[i] = term.Source
+	}
+	dictionary.provenance = make([]SourceSet, len(sorted))
+	for i, w := range sorted {
+		dictionary.provenance[i] = provenance[string(w)]
+	}
+	// the metadata of a word is taken from its sources from left to right
+	contents = slices.DeleteFunc(contents, func(content CorpusContent) bool {
+		c, ok := content.(*corpusContent)
+		return ok && c.metadata == nil
+	})
+	if len(contents) > 0 {
+		dictionary.metadata = make(Metadata)
+		for _, w := range sorted {
+			var metadata WordMetadata
+			for _, content := range contents {
+				if m, found := content.Metadata(w); found {
+					metadata.merge(m)
+				}
+			}
+			if !metadata.Empty() {
+				dictionary.metadata[w.String(corpus)] = metadata
+			}
+		}
+	}
+	return dictionary, nil
+}
@@ -0,0 +1,45 @@
+package corpus
+
+import (
+	"slices"
+)
+
+// CorpusDiff is the words added and removed when a corpus content is replaced by another
+type CorpusDiff struct {
+	Added           Words
+	Removed         Words
+	AddedByLength   map[int]int // the number of added words by word length
+	RemovedByLength map[int]int // the number of removed words by word length
+}
+
+// DiffContents compares the sorted words of the contents of the same corpus
+func DiffContents(old CorpusContent, new CorpusContent) CorpusDiff {
+	diff := CorpusDiff{Added: Words{}, Removed: Words{}, AddedByLength: make(map[int]int), RemovedByLength: make(map[int]int)}
+	oldWords, newWords := old.Words(), new.Words()
+	i, j := 0, 0
+	for i < len(oldWords) || j < len(newWords) {
+		c := 0
+		switch {
+		case i == len(oldWords):
+			c = 1
+		case j == len(newWords):
+			c = -1
+		default:
+			c = slices.Compare(oldWords[i], newWords[j])
+		}
+		switch {
+		case c < 0:
+			diff.Removed = append(diff.Removed, oldWords[i])
+			diff.RemovedByLength[len(oldWords[i])]++
+			i++
+		case c > 0:
+			diff.Added = append(diff.Added, newWords[j])
+			diff.AddedByLength[len(newWords[j])]++
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return diff
+}
@@ -0,0 +1,134 @@
+package corpus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// A corpus file (and the files next to it - the compiled dawg and the metadata) is read
+// from the first of
+//
+//	fileName        on disk - gzip compressed when the name ends with ".gz"
+//	fileName.gz     on disk
+//	fileName        in the embedded files by base name - see EmbedCorpusFiles
+//	fileName.gz     in the embedded files by base name
+const gzipFileExtension = ".gz"
+
+// the files built into the binary - nil when there are none
+var embeddedFiles fs.FS
+
+// EmbedCorpusFiles makes the files of fsys available as corpus files which are not on disk
+// - a file is found by the base name of the corpus file name
+func EmbedCorpusFiles(fsys fs.FS) {
+	embeddedFiles = fsys
+}
+
+// CorpusFileBase returns fileName without its extension and a gzip extension
+// - "data/corpus_dk.txt.gz" gives "data/corpus_dk"
+func CorpusFileBase(fileName string) string {
+	fileName = strings.TrimSuffix(fileName, gzipFileExtension)
+	return strings.TrimSuffix(fileName, path.Ext(fileName))
+}
+
+type corpusFile struct {
+	fsys   fs.FS // nil for a file on disk
+	name   string
+	info   fs.FileInfo
+	gzip   bool
+	origin string // the name of the file read for messages
+}
+
+func locateCorpusFile(fileName string) (*corpusFile, error) {
+	names := []string{fileName}
+	if !strings.HasSuffix(fileName, gzipFileExtension) {
+		names = append(names, fileName+gzipFileExtension)
+	}
+	for _, name := range names {
+		info, err := os.Stat(name)
+		if err == nil {
+			return &corpusFile{name: name, info: info, gzip: strings.HasSuffix(name, gzipFileExtension), origin: name}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if embeddedFiles != nil {
+		for _, name := range names {
+			name = path.Base(name)
+			if info, err := fs.Stat(embeddedFiles, name); err == nil {
+				return &corpusFile{fsys: embeddedFiles, name: name, info: info, gzip: strings.HasSuffix(name, gzipFileExtension), origin: "embedded " + name}, nil
+			}
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: fileName, Err: fs.ErrNotExist}
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f io.Closer
+}
+
+func (file gzipFile) Close() error {
+	err := file.Reader.Close()
+	if cerr := file.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// OpenCorpusFile opens the corpus file fileName for reading - decompressed when it is gzip compressed
+func OpenCorpusFile(fileName string) (io.ReadCloser, error) {
+	file, err := locateCorpusFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var f io.ReadCloser
+	if file.fsys != nil {
+		f, err = file.fsys.Open(file.name)
+	} else {
+		f, err = os.Open(file.name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !file.gzip {
+		return f, nil
+	}
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("gzip file \"%s\" : %v", file.origin, err)
+	}
+	return gzipFile{Reader: r, f: f}, nil
+}
+
+// ReadCorpusFile returns the (decompressed) content of the corpus file fileName
+func ReadCorpusFile(fileName string) ([]byte, error) {
+	f, err := OpenCorpusFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(f); err != nil {
+		return nil, fmt.Errorf("corpus file \"%s\" : %v", fileName, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// CorpusFileVersion identifies the version of the corpus file fileName by the name, size and
+// modification time of the file read
+func CorpusFileVersion(fileName string) (string, error) {
+	file, err := locateCorpusFile(fileName)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%d-%d", file.origin, file.info.Size(), file.info.ModTime().UnixNano()), nil
+}
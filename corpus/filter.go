@@ -0,0 +1,197 @@
+package corpus
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// BOARD_DIMENSION is the width and height of the standard board - a longer word can never be played
+const BOARD_DIMENSION = 15
+
+// A FilterRule excludes words of the word lists of a language.
+// The alphabet and minimum word length rules apply to every language - the rules of the
+// language definition are applied after those in the order they are defined.
+type FilterRule struct {
+	Name     string     `json:"name"`
+	Kind     FilterKind `json:"kind"`
+	Length   int        `json:"length,omitempty"`  // FILTER_MAX_LENGTH - the longest word kept - 0 is BOARD_DIMENSION
+	Words    []string   `json:"words,omitempty"`   // FILTER_EXCLUDE_LIST - the words excluded
+	FileName string     `json:"file,omitempty"`    // FILTER_EXCLUDE_LIST - a file of excluded words (one per line) - a missing file excludes nothing
+	Pattern  string     `json:"pattern,omitempty"` // FILTER_EXCLUDE_REGEXP - excludes the (upper case) words matching the pattern
+	POS      []string   `json:"pos,omitempty"`     // FILTER_EXCLUDE_POS - the parts of speech excluded by corpus import when a source has a part of speech column
+}
+
+type FilterKind byte
+
+const (
+	FILTER_ALPHABET       = FilterKind(0)
+	FILTER_MIN_LENGTH     = FilterKind(1)
+	FILTER_MAX_LENGTH     = FilterKind(2)
+	FILTER_EXCLUDE_LIST   = FilterKind(3)
+	FILTER_EXCLUDE_REGEXP = FilterKind(4)
+	FILTER_EXCLUDE_POS    = FilterKind(5)
+)
+
+// FilterCount is the number of words removed by a rule
+type FilterCount struct {
+	Rule  string
+	Count int
+}
+
+type wordFilter struct {
+	rule    FilterRule
+	exclude map[string]bool
+	regexp  *regexp.Regexp
+}
+
+func (kind FilterKind) String() string {
+	switch kind {
+	case FILTER_ALPHABET:
+		return "alphabet"
+	case FILTER_MIN_LENGTH:
+		return "min length"
+	case FILTER_MAX_LENGTH:
+		return "max length"
+	case FILTER_EXCLUDE_LIST:
+		return "exclude list"
+	case FILTER_EXCLUDE_REGEXP:
+		return "exclude regexp"
+	case FILTER_EXCLUDE_POS:
+		return "exclude pos"
+	}
+	panic(fmt.Sprintf("illegal FilterKind %d (FilterKind.String)", kind))
+}
+
+func (kind FilterKind) MarshalText() ([]byte, error) {
+	return []byte(kind.String()), nil
+}
+
+func (kind *FilterKind) UnmarshalText(text []byte) error {
+	for k := FILTER_ALPHABET; k <= FILTER_EXCLUDE_POS; k++ {
+		if k.String() == string(text) {
+			*kind = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown filter kind \"%s\"", string(text))
+}
+
+func (rule FilterRule) String() string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return rule.Kind.String()
+}
+
+func newWordFilter(rule FilterRule) (*wordFilter, error) {
+	filter := &wordFilter{rule: rule}
+	var err error
+	switch rule.Kind {
+	case FILTER_MAX_LENGTH:
+		if filter.rule.Length <= 0 {
+			filter.rule.Length = BOARD_DIMENSION
+		}
+	case FILTER_EXCLUDE_LIST:
+		filter.exclude = make(map[string]bool)
+		for _, w := range rule.Words {
+			filter.exclude[strings.ToUpper(w)] = true
+		}
+		if rule.FileName != "" {
+			err = readExcludeFile(rule.FileName, filter.exclude)
+		}
+	case FILTER_EXCLUDE_REGEXP:
+		filter.regexp, err = regexp.Compile(rule.Pattern)
+	case FILTER_EXCLUDE_POS:
+		filter.exclude = make(map[string]bool)
+		for _, pos := range rule.POS {
+			filter.exclude[pos] = true
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("filter rule \"%s\" : %s", rule.String(), err.Error())
+	}
+	return filter, nil
+}
+
+func readExcludeFile(fileName string, exclude map[string]bool) error {
+	f, err := os.Open(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if line := strings.TrimSpace(s.Text()); line != "" && line[0] != '#' {
+			exclude[strings.ToUpper(line)] = true
+		}
+	}
+	return s.Err()
+}
+
+// excludes tells if the upper case word is removed by the filter - pos is the part of speech when known
+func (filter *wordFilter) excludes(word string, length int, pos string) bool {
+	switch filter.rule.Kind {
+	case FILTER_MAX_LENGTH:
+		return length > filter.rule.Length
+	case FILTER_EXCLUDE_LIST:
+		return filter.exclude[word]
+	case FILTER_EXCLUDE_REGEXP:
+		return filter.regexp.MatchString(word)
+	case FILTER_EXCLUDE_POS:
+		return pos != "" && filter.exclude[pos]
+	}
+	return false
+}
+
+// posFilter returns the part of speech rule excluding pos - nil when pos is kept
+func (corpus *corpusData) posFilter(pos string) *wordFilter {
+	if pos == "" {
+		return nil
+	}
+	for _, filter := range corpus.filters {
+		if filter.rule.Kind == FILTER_EXCLUDE_POS && filter.excludes("", 0, pos) {
+			return filter
+		}
+	}
+	return nil
+}
+
+// filterCounts makes the counts of the built in and the language rules
+func (corpus *corpusData) filterCounts() []FilterCount {
+	counts := make([]FilterCount, 0, len(corpus.filters)+2)
+	counts = append(counts, FilterCount{Rule: FILTER_ALPHABET.String()}, FilterCount{Rule: FILTER_MIN_LENGTH.String()})
+	for _, filter := range corpus.wordFilters() {
+		counts = append(counts, FilterCount{Rule: filter.rule.String()})
+	}
+	return counts
+}
+
+// wordFilters returns the language rules applied to the words of corpus files - the part of speech
+// rules are applied by corpus import only as the words of a corpus file have no part of speech
+func (corpus *corpusData) wordFilters() []*wordFilter {
+	filters := make([]*wordFilter, 0, len(corpus.filters))
+	for _, filter := range corpus.filters {
+		if filter.rule.Kind != FILTER_EXCLUDE_POS {
+			filters = append(filters, filter)
+		}
+	}
+	return filters
+}
+
+// filterWord returns the index in filterCounts of the first of the wordFilters excluding word - or -1 when it is kept
+func filterWord(filters []*wordFilter, word string, length int) int {
+	for i, filter := range filters {
+		if filter.excludes(word, length, "") {
+			return i + 2
+		}
+	}
+	return -1
+}
@@ -0,0 +1,229 @@
+package corpus
+
+import (
+	"archive/zip"
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ImportSource is a word list file (plain text, TSV, CSV or a zip archive of those) to import
+type ImportSource struct {
+	FileName  string
+	Column    int    // the 1-based field holding the word - 0 for the whole line
+	Separator string // the field separator - tab when empty
+	Members   string // pattern of the files to read in a zip archive - all files when empty
+	POSColumn int    // the 1-based field holding the part of speech of the word - 0 when there is none
+	// the 1-based fields holding the metadata of the word - 0 when there is none
+	LemmaColumn     int
+	FrequencyColumn int // a number - the higher the more frequent the word is
+	GlossColumn     int
+}
+
+type ImportReject struct {
+	Source string
+	Line   int
+	Text   string
+	Reason string
+}
+
+type ImportReport struct {
+	Lines      int
+	Duplicates int
+	Words      []string // the imported words in lower case and byte order (as sort with LC_COLLATE=C)
+	Rejected   []ImportReject
+	Metadata   Metadata // the metadata of the imported words when a source has metadata columns
+}
+
+const (
+	REJECT_MISSING_COLUMN = "missing column"
+	REJECT_ALPHABET       = "not in alphabet"
+	REJECT_TOO_SHORT      = "too short"
+)
+
+const importMaxLineLength = 1024 * 1024
+
+// ParseImportSource parses "file" or "file:column" where column is the 1-based field holding the word
+func ParseImportSource(spec string, separator string) (ImportSource, error) {
+	source := ImportSource{FileName: spec, Separator: separator}
+	if i := strings.LastIndexByte(spec, ':'); i >= 0 {
+		column, err := strconv.Atoi(spec[i+1:])
+		if err != nil || column < 0 {
+			return source, fmt.Errorf("invalid column in import source \"%s\" - expected file:column", spec)
+		}
+		source.FileName = spec[:i]
+		source.Column = column
+	}
+	return source, nil
+}
+
+// ImportWords reads the words of sources. A word is normalized to NFC and accepted when it is made of
+// letters of the alphabet (in any case) and is not shorter than the minimum word length - like scanWords.
+// The part of speech rules of the language are applied when a source has a part of speech column - the
+// other filter rules are applied when the corpus file is read.
+func ImportWords(corpus Corpus, sources []ImportSource) (*ImportReport, error) {
+	data := corpus.(*corpusData)
+	wordRegexp, err := data.wordRegexp()
+	if err != nil {
+		return nil, err
+	}
+	report := &ImportReport{Words: make([]string, 0, 10000), Rejected: make([]ImportReject, 0), Metadata: make(Metadata)}
+	seen := make(map[string]bool)
+	frequencies := make(map[string]float64)
+	importReader := func(source ImportSource, name string, r io.Reader) error {
+		separator := source.Separator
+		if separator == "" {
+			separator = "\t"
+		}
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 0, 64*1024), importMaxLineLength)
+		for lineNo := 1; s.Scan(); lineNo++ {
+			report.Lines++
+			line := s.Text()
+			if lineNo == 1 {
+				line = strings.TrimPrefix(line, "\ufeff")
+			}
+			field := line
+			if source.Column > 0 {
+				fields := strings.Split(line, separator)
+				if source.Column > len(fields) {
+					report.Rejected = append(report.Rejected, ImportReject{name, lineNo, line, REJECT_MISSING_COLUMN})
+					continue
+				}
+				field = fields[source.Column-1]
+			}
+			fields := strings.Split(line, separator)
+			column := func(column int) string {
+				if column <= 0 || column > len(fields) {
+					return ""
+				}
+				return strings.Join(strings.Fields(strings.Trim(fields[column-1], "\"")), " ")
+			}
+			pos := column(source.POSColumn)
+			field = strings.Trim(strings.TrimSpace(field), "\"")
+			if field == "" {
+				continue
+			}
+			word := strings.ToUpper(norm.NFC.String(field))
+			switch {
+			case !wordRegexp.MatchString(word):
+				report.Rejected = append(report.Rejected, ImportReject{name, lineNo, line, REJECT_ALPHABET})
+			case len(data.stringToWord(word)) < data.minWordLength:
+				report.Rejected = append(report.Rejected, ImportReject{name, lineNo, line, REJECT_TOO_SHORT})
+			case data.posFilter(pos) != nil:
+				report.Rejected = append(report.Rejected, ImportReject{name, lineNo, line, data.posFilter(pos).rule.String()})
+			case seen[word]:
+				report.Duplicates++
+				report.addMetadata(word, source, pos, column, frequencies)
+			default:
+				seen[word] = true
+				report.Words = append(report.Words, strings.ToLower(word))
+				report.addMetadata(word, source, pos, column, frequencies)
+			}
+		}
+		return s.Err()
+	}
+	for _, source := range sources {
+		if err := importSource(source, importReader); err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(report.Words)
+	// the most frequent word has rank 1
+	ranked := slices.Collect(maps.Keys(frequencies))
+	slices.SortFunc(ranked, func(l string, r string) int {
+		return cmp.Or(cmp.Compare(frequencies[r], frequencies[l]), strings.Compare(l, r))
+	})
+	for i, word := range ranked {
+		metadata := report.Metadata[word]
+		metadata.FrequencyRank = i + 1
+		report.Metadata[word] = metadata
+	}
+	return report, nil
+}
+
+// addMetadata adds the metadata of the metadata columns of source to the metadata of word
+func (report *ImportReport) addMetadata(word string, source ImportSource, pos string, column func(int) string, frequencies map[string]float64) {
+	metadata := WordMetadata{Lemma: strings.ToLower(column(source.LemmaColumn)), POS: pos, Gloss: column(source.GlossColumn)}
+	if frequency, err := strconv.ParseFloat(column(source.FrequencyColumn), 64); err == nil && frequency > frequencies[word] {
+		frequencies[word] = frequency
+	}
+	if !metadata.Empty() {
+		m := report.Metadata[word]
+		m.merge(metadata)
+		report.Metadata[word] = m
+	}
+}
+
+func importSource(source ImportSource, importReader func(ImportSource, string, io.Reader) error) error {
+	if strings.ToLower(path.Ext(source.FileName)) != ".zip" {
+		f, err := OpenCorpusFile(source.FileName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return importReader(source, source.FileName, f)
+	}
+	archive, err := zip.OpenReader(source.FileName)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	for _, member := range archive.File {
+		if member.FileInfo().IsDir() {
+			continue
+		}
+		if source.Members != "" {
+			if match, err := path.Match(source.Members, path.Base(member.Name)); err != nil || !match {
+				continue
+			}
+		}
+		f, err := member.Open()
+		if err != nil {
+			return err
+		}
+		err = importReader(source, source.FileName+"/"+member.Name, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCorpusFile writes the imported words to fileName - one word per line
+func (report *ImportReport) WriteCorpusFile(fileName string) error {
+	var sb strings.Builder
+	for _, w := range report.Words {
+		sb.WriteString(w)
+		sb.WriteByte('\n')
+	}
+	tmpFileName := fileName + "~"
+	if err := os.WriteFile(tmpFileName, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFileName, fileName)
+}
+
+// WriteMetadataFile writes the metadata of the imported words to the metadata file of the corpus file fileName
+func (report *ImportReport) WriteMetadataFile(fileName string) error {
+	return WriteMetadataFile(MetadataFileName(fileName), report.Metadata)
+}
+
+// WriteRejectFile writes the rejected lines to fileName as "source:line: reason: text"
+func (report *ImportReport) WriteRejectFile(fileName string) error {
+	var sb strings.Builder
+	for _, reject := range report.Rejected {
+		fmt.Fprintf(&sb, "%s:%d: %s: %s\n", reject.Source, reject.Line, reject.Reason, reject.Text)
+	}
+	return os.WriteFile(fileName, []byte(sb.String()), 0644)
+}
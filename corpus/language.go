@@ -1,118 +1,275 @@
 package corpus
 
 import (
+	"cmp"
+	"embed"
+	"encoding/json"
 	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
 	"unicode"
 
 	"golang.org/x/text/collate"
 	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
+// The language definitions are JSON files - the definitions built into the program are in
+// corpus/languages and the files of LanguageDirectory add languages or replace built in definitions.
+//
+//	{
+//		"language": "da",                           the BCP 47 tag of the language
+//		"collation": "da",                          the collation tag - default is the language
+//		"names": {"da": "dansk", "en": "Danish"},   the name of the language in other languages
+//		"corpus": "corpus_dk.txt",                  the corpus file in the data directory
+//		"blanks": 2,                                the number of blank tiles (jokers)
+//		"tiles": [{"letter": "A", "count": 7, "value": 1}, ...],   a letter may be a digraph like "CH"
+//		"sources": {"ddo": "ddo.txt", ...},         the word lists which can be combined into a dictionary
+//		"filters": [{"name": "board dimension", "kind": "max length"}, ...]
+//	}
+const LanguageDirectory = "data/languages"
+
+const languageDataDirectory = "data"
+
+//go:embed languages/*.json
+var builtinLanguages embed.FS
+
 type languageTile struct {
-	character rune
+	character string
 	count     byte
 	value     byte
 }
 
 type LanguageTiles []languageTile
 type languageDefinition struct {
-	language    language.Tag
-	initialized bool
-	collator    *collate.Collator
-	alphabet    Alphabet
-	fileName    string
-	pieces      LanguageTiles // string with all vowels
-}
-
-var languageDefinitions = map[language.Tag]*languageDefinition{
-	language.Danish: {
-		language:    language.Danish,
-		initialized: false,
-		collator:    nil,
-		alphabet:    Alphabet{},
-		fileName:    "corpus_dk.txt",
-		pieces: LanguageTiles{
-			languageTile{'A', 7, 1},
-			languageTile{'B', 4, 3},
-			languageTile{'C', 2, 8},
-			languageTile{'D', 5, 2},
-			languageTile{'E', 9, 1},
-			languageTile{'F', 3, 3},
-			languageTile{'G', 3, 3},
-			languageTile{'H', 2, 4},
-			languageTile{'I', 4, 3},
-			languageTile{'J', 2, 4},
-			languageTile{'K', 4, 3},
-			languageTile{'L', 5, 2},
-			languageTile{'M', 3, 4},
-			languageTile{'N', 7, 1},
-			languageTile{'O', 5, 2},
-			languageTile{'P', 2, 4},
-			languageTile{'R', 7, 1},
-			languageTile{'S', 6, 2},
-			languageTile{'T', 6, 2},
-			languageTile{'U', 3, 3},
-			languageTile{'V', 3, 4},
-			languageTile{'X', 1, 8},
-			languageTile{'Y', 2, 4},
-			languageTile{'Z', 1, 9},
-			languageTile{'Æ', 2, 4},
-			languageTile{'Ø', 2, 4},
-			languageTile{'Å', 2, 4},
-		}},
-
-	//language.English: {fileName: "corpus_en.txt", validCharacters: "a-z", vowels: "aeiouy"},
-}
-
-func (def *languageDefinition) init() {
-	if def.initialized {
-		return
-	}
-	def.collator = collate.New(def.language)
-	def.fileName = fmt.Sprintf("data/%s", def.fileName)
-	characters := make([]string, len(def.pieces))
+	language language.Tag
+	collator *collate.Collator
+	alphabet Alphabet
+	names    map[string]string
+	fileName string
+	blanks   int
+	sources  map[string]string // word list files which can be combined into a dictionary - by name
+	filters  []FilterRule      // the rules removing words from the word lists
+	pieces   LanguageTiles     // string with all vowels
+	file     languageFile      // the definition as read
+}
+
+type languageFile struct {
+	Language  string             `json:"language"`
+	Collation string             `json:"collation,omitempty"`
+	Names     map[string]string  `json:"names,omitempty"`
+	Corpus    string             `json:"corpus"`
+	Blanks    int                `json:"blanks"`
+	Tiles     []languageFileTile `json:"tiles"`
+	Sources   map[string]string  `json:"sources,omitempty"`
+	Filters   []FilterRule       `json:"filters,omitempty"`
+}
+
+type languageFileTile struct {
+	Letter string `json:"letter"`
+	Count  int    `json:"count"`
+	Value  int    `json:"value"`
+}
+
+var languages = struct {
+	sync.Mutex
+	loaded      bool
+	definitions map[language.Tag]*languageDefinition
+}{}
+
+func parseLanguageDefinition(data []byte, fileName string) (*languageDefinition, error) {
+	var file languageFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("language definition \"%s\" : %s", fileName, err.Error())
+	}
+	Errorf := func(format string, args ...any) error {
+		return fmt.Errorf("language definition \"%s\" : %s", fileName, fmt.Sprintf(format, args...))
+	}
+	tag, err := language.Parse(file.Language)
+	if err != nil {
+		return nil, Errorf("invalid language \"%s\"", file.Language)
+	}
+	collation := tag
+	if file.Collation != "" {
+		if collation, err = language.Parse(file.Collation); err != nil {
+			return nil, Errorf("invalid collation \"%s\"", file.Collation)
+		}
+	}
+	if file.Corpus == "" {
+		return nil, Errorf("no corpus file")
+	}
+	if file.Blanks < 0 {
+		return nil, Errorf("negative number of blanks")
+	}
+	def := &languageDefinition{
+		language: tag,
+		collator: collate.New(collation),
+		names:    file.Names,
+		fileName: path.Join(languageDataDirectory, file.Corpus),
+		blanks:   file.Blanks,
+		sources:  make(map[string]string),
+		filters:  slices.Clone(file.Filters),
+		pieces:   make(LanguageTiles, len(file.Tiles)),
+		file:     file,
+	}
+	for i, t := range file.Tiles {
+		letter := strings.ToUpper(norm.NFC.String(t.Letter))
+		if letter == "" || strings.IndexFunc(letter, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+			return nil, Errorf("tile \"%s\" is not made of letters", t.Letter)
+		}
+		if t.Count < 0 || t.Count > 255 || t.Value < 0 || t.Value > 255 {
+			return nil, Errorf("tile \"%s\" has an invalid count or value", t.Letter)
+		}
+		def.pieces[i] = languageTile{character: letter, count: byte(t.Count), value: byte(t.Value)}
+	}
+	for name, fileName := range file.Sources {
+		def.sources[name] = path.Join(languageDataDirectory, fileName)
+	}
+	for i, rule := range def.filters {
+		if rule.FileName != "" {
+			def.filters[i].FileName = path.Join(languageDataDirectory, rule.FileName)
+		}
+	}
+	def.alphabet = make(Alphabet, len(def.pieces))
 	for i, p := range def.pieces {
-		p.character = unicode.ToUpper(p.character)
-		characters[i] = string(p.character)
+		def.alphabet[i] = p.character
+	}
+	sort.Strings(def.alphabet)
+	if len(slices.Compact(slices.Clone(def.alphabet))) != len(def.alphabet) {
+		return nil, Errorf("a letter has more than one tile definition")
+	}
+	return def, nil
+}
+
+// readLanguageDefinitions reads the *.json language definitions of directory in fsys
+func readLanguageDefinitions(fsys fs.FS, directory string) ([]*languageDefinition, error) {
+	fileNames, err := fs.Glob(fsys, path.Join(directory, "*.json"))
+	if err != nil {
+		return nil, err
 	}
-	sort.Strings(characters)
-	def.alphabet = make(Alphabet, len(characters))
-	for i, s := range characters {
-		def.alphabet[i] = []rune(s)[0]
+	definitions := make([]*languageDefinition, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		data, err := fs.ReadFile(fsys, fileName)
+		if err != nil {
+			return nil, err
+		}
+		def, err := parseLanguageDefinition(data, fileName)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, def)
 	}
-	def.initialized = true
+	return definitions, nil
+}
+
+// LoadLanguages makes the languages the built in languages and the languages defined in directory.
+// A definition in directory replaces the built in definition of the language.
+// Until LoadLanguages is called only the built in languages are defined.
+func LoadLanguages(directory string) error {
+	definitions, err := loadLanguages(directory)
+	if err != nil {
+		return err
+	}
+	languages.Lock()
+	defer languages.Unlock()
+	languages.definitions = definitions
+	languages.loaded = true
+	return nil
+}
+
+func loadLanguages(directory string) (map[language.Tag]*languageDefinition, error) {
+	definitions, err := readLanguageDefinitions(builtinLanguages, "languages")
+	if err != nil {
+		panic(fmt.Sprintf("invalid built in language definition : %s", err.Error()))
+	}
+	if info, err := os.Stat(directory); err == nil && info.IsDir() {
+		more, err := readLanguageDefinitions(os.DirFS(directory), ".")
+		if err != nil {
+			return nil, fmt.Errorf("%s : %s", directory, err.Error())
+		}
+		definitions = append(definitions, more...)
+	}
+	languageDefinitions := make(map[language.Tag]*languageDefinition)
+	for _, def := range definitions {
+		languageDefinitions[def.language] = def
+	}
+	return languageDefinitions, nil
+}
+
+func languageDefinitions() map[language.Tag]*languageDefinition {
+	languages.Lock()
+	defer languages.Unlock()
+	if !languages.loaded {
+		languages.definitions, _ = loadLanguages("")
+		languages.loaded = true
+	}
+	return languages.definitions
 }
 
 func SupportedLanguage(language language.Tag) bool {
-	_, ok := languageDefinitions[language]
+	_, ok := languageDefinitions()[language]
 	return ok
 }
 
+// SupportedLanguages returns the languages with a definition
+func SupportedLanguages() []language.Tag {
+	tags := make([]language.Tag, 0)
+	for tag := range languageDefinitions() {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, func(l language.Tag, r language.Tag) int { return cmp.Compare(l.String(), r.String()) })
+	return tags
+}
+
 func getDefinition(language language.Tag) *languageDefinition {
-	definition, ok := languageDefinitions[language]
+	definition, ok := languageDefinitions()[language]
 	if !ok {
 		panic(fmt.Sprintf("unsupported language %s", language.String()))
 	}
-	if !definition.initialized {
-		definition.init()
-	}
 	return definition
 }
 
+// GetLanguageName returns the name of language in the language in - or the tag of language when it is unknown
+func GetLanguageName(language language.Tag, in language.Tag) string {
+	base, _ := in.Base()
+	if name, found := getDefinition(language).names[base.String()]; found {
+		return name
+	}
+	return language.String()
+}
+
 func GetLanguageFileName(language language.Tag) string {
 	return getDefinition(language).fileName
 }
 
+// GetLanguageSources returns the file names of the word lists of language by source name
+func GetLanguageSources(language language.Tag) map[string]string {
+	return getDefinition(language).sources
+}
+
+func GetLanguageFilters(language language.Tag) []FilterRule {
+	return getDefinition(language).filters
+}
+
 func GetLanguageTiles(language language.Tag) LanguageTiles {
 	return getDefinition(language).pieces
 }
 
+// GetLanguageBlanks returns the number of blank tiles (jokers) of language
+func GetLanguageBlanks(language language.Tag) int {
+	return getDefinition(language).blanks
+}
+
 func GetLanguageAlphabet(language language.Tag) Alphabet {
 	return getDefinition(language).alphabet
 }
 
-func (def *languageTile) Character() rune {
+func (def *languageTile) Character() string {
 	return def.character
 }
 
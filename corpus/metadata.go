@@ -0,0 +1,109 @@
+package corpus
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// WordMetadata is what the word lists tell about a word
+type WordMetadata struct {
+	Lemma         string `json:"lemma,omitempty"`         // the dictionary form of the word
+	POS           string `json:"pos,omitempty"`           // the part of speech
+	FrequencyRank int    `json:"frequencyRank,omitempty"` // 1 is the most frequent word - 0 when unknown
+	Gloss         string `json:"gloss,omitempty"`         // a short explanation of the word
+}
+
+// Metadata is the metadata of words by the upper case word
+type Metadata map[string]WordMetadata
+
+// The metadata of the words of a corpus file is kept in a tab separated file next to it
+// with a line per word "word lemma pos rank gloss" where all but the word may be empty.
+const metadataFileExtension = ".meta"
+
+func MetadataFileName(corpusFileName string) string {
+	return CorpusFileBase(corpusFileName) + metadataFileExtension
+}
+
+func (metadata WordMetadata) Empty() bool {
+	return metadata == WordMetadata{}
+}
+
+// merge fills the fields of metadata which are empty from other - the part of speech and gloss
+// only when other is the metadata of the same lemma
+func (metadata *WordMetadata) merge(other WordMetadata) {
+	if metadata.FrequencyRank == 0 {
+		metadata.FrequencyRank = other.FrequencyRank
+	}
+	if metadata.Lemma != "" && other.Lemma != "" && metadata.Lemma != other.Lemma {
+		return
+	}
+	if metadata.Lemma == "" {
+		metadata.Lemma = other.Lemma
+	}
+	if metadata.POS == "" {
+		metadata.POS = other.POS
+	}
+	if metadata.Gloss == "" {
+		metadata.Gloss = other.Gloss
+	}
+}
+
+// ReadMetadataFile reads the metadata persisted in fileName - a missing file is no metadata (nil)
+func ReadMetadataFile(fileName string) (Metadata, error) {
+	f, err := OpenCorpusFile(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	metadata := make(Metadata)
+	s := bufio.NewScanner(f)
+	for lineNo := 1; s.Scan(); lineNo++ {
+		line := s.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		for len(fields) < 5 {
+			fields = append(fields, "")
+		}
+		var rank int
+		if fields[3] != "" {
+			if rank, err = strconv.Atoi(fields[3]); err != nil {
+				return nil, fmt.Errorf("metadata file \"%s\" line %d : invalid frequency rank \"%s\"", fileName, lineNo, fields[3])
+			}
+		}
+		metadata[strings.ToUpper(fields[0])] = WordMetadata{Lemma: fields[1], POS: fields[2], FrequencyRank: rank, Gloss: fields[4]}
+	}
+	return metadata, s.Err()
+}
+
+// WriteMetadataFile persists metadata in fileName in byte order of the words - no metadata removes the file
+func WriteMetadataFile(fileName string, metadata Metadata) error {
+	if len(metadata) == 0 {
+		err := os.Remove(fileName)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var sb strings.Builder
+	for _, word := range slices.Sorted(maps.Keys(metadata)) {
+		m := metadata[word]
+		rank := ""
+		if m.FrequencyRank > 0 {
+			rank = strconv.Itoa(m.FrequencyRank)
+		}
+		fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\t%s\n", strings.ToLower(word), m.Lemma, m.POS, rank, m.Gloss)
+	}
+	return os.WriteFile(fileName, []byte(sb.String()), 0644)
+}
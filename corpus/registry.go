@@ -0,0 +1,158 @@
+package corpus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/text/language"
+)
+
+// The registry hands out Corpus and CorpusContent instances shared by all games in the process.
+// Shared instances must be treated as immutable. Failures are not shared - the next call tries again.
+
+const contentCacheSize = 10
+
+type corpusKey struct {
+	language language.Tag
+	fileName string // or the spec of a dictionary
+	version  string // size and modification time of the file - a changed file is a new version
+}
+
+type corpusEntry struct {
+	once    sync.Once
+	corpus  Corpus
+	content CorpusContent
+	err     error
+}
+
+var registry = struct {
+	sync.Mutex
+	corpora  map[language.Tag]*corpusEntry
+	contents *lru.Cache
+}{
+	corpora: make(map[language.Tag]*corpusEntry),
+}
+
+func SharedCorpus(lang language.Tag) (Corpus, error) {
+	if !SupportedLanguage(lang) {
+		return nil, fmt.Errorf("unsupported language \"%s\"", lang.String())
+	}
+	registry.Lock()
+	entry, found := registry.corpora[lang]
+	if !found {
+		entry = &corpusEntry{}
+		registry.corpora[lang] = entry
+	}
+	registry.Unlock()
+	entry.once.Do(func() {
+		entry.corpus, entry.err = NewCorpus(lang)
+	})
+	if entry.err != nil {
+		registry.Lock()
+		if registry.corpora[lang] == entry {
+			delete(registry.corpora, lang)
+		}
+		registry.Unlock()
+	}
+	return entry.corpus, entry.err
+}
+
+func SharedFileContent(corpus Corpus, fileName string) (CorpusContent, error) {
+	version, err := CorpusFileVersion(fileName)
+	if err != nil {
+		return nil, err
+	}
+	key := corpusKey{
+		language: corpus.Language(),
+		fileName: fileName,
+		version:  version,
+	}
+	if version, err := CorpusFileVersion(MetadataFileName(fileName)); err == nil {
+		key.version += "+" + version
+	}
+	entry := contentEntry(corpus, key)
+	entry.once.Do(func() {
+		entry.content, entry.err = corpus.GetFileContent(fileName)
+	})
+	if entry.err != nil {
+		removeContentEntry(key, entry)
+	}
+	if entry.err == nil && entry.content.Corpus() != corpus {
+		return nil, fmt.Errorf("shared content of \"%s\" belongs to another corpus instance - use SharedCorpus", fileName)
+	}
+	return entry.content, entry.err
+}
+
+func SharedLanguageContent(lang language.Tag) (CorpusContent, error) {
+	corpus, err := SharedCorpus(lang)
+	if err != nil {
+		return nil, err
+	}
+	return SharedFileContent(corpus, GetLanguageFileName(lang))
+}
+
+// SharedDictionaryContent returns the content of the dictionary spec of lang - the language corpus file when spec is empty.
+// A dictionary is a new version when any of its source files changes.
+func SharedDictionaryContent(lang language.Tag, specStr string) (CorpusContent, error) {
+	if specStr == "" {
+		return SharedLanguageContent(lang)
+	}
+	corpus, err := SharedCorpus(lang)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := ParseDictionarySpec(specStr)
+	if err != nil {
+		return nil, err
+	}
+	fileNames, err := spec.SourceFiles(lang)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, len(fileNames))
+	for i, fileName := range fileNames {
+		if versions[i], err = CorpusFileVersion(fileName); err != nil {
+			return nil, err
+		}
+	}
+	key := corpusKey{
+		language: lang,
+		fileName: spec.String(),
+		version:  strings.Join(versions, ","),
+	}
+	entry := contentEntry(corpus, key)
+	entry.once.Do(func() {
+		entry.content, entry.err = NewDictionaryContent(corpus, spec, func(fileName string) (CorpusContent, error) {
+			return SharedFileContent(corpus, fileName)
+		})
+	})
+	if entry.err != nil {
+		removeContentEntry(key, entry)
+	}
+	return entry.content, entry.err
+}
+
+func contentEntry(corpus Corpus, key corpusKey) *corpusEntry {
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.contents == nil {
+		registry.contents, _ = lru.New(contentCacheSize)
+	}
+	if cached, found := registry.contents.Get(key); found {
+		return cached.(*corpusEntry)
+	}
+	entry := &corpusEntry{corpus: corpus}
+	registry.contents.Add(key, entry)
+	return entry
+}
+
+// removeContentEntry removes the failed entry of key unless it has been replaced
+func removeContentEntry(key corpusKey, entry *corpusEntry) {
+	registry.Lock()
+	defer registry.Unlock()
+	if cached, found := registry.contents.Peek(key); found && cached.(*corpusEntry) == entry {
+		registry.contents.Remove(key)
+	}
+}
@@ -0,0 +1,191 @@
+package corpus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// TILES_RACK_LENGTH is the longest word counted by the tile statistics - the rack and a letter on the board
+const TILES_RACK_LENGTH = 8
+
+const (
+	TILES_MIN_VALUE = 1
+	TILES_MAX_VALUE = 10
+)
+
+// TileStat is the statistics of a letter in the words of a content and the tile count and value proposed
+type TileStat struct {
+	Letter       string  `json:"letter"`
+	Frequency    float64 `json:"frequency"` // the share of the letters of the words which are this letter
+	WordShare    float64 `json:"wordShare"` // the share of the words holding the letter
+	TwoLetter    float64 `json:"twoLetter"` // the share of the two letter words holding the letter
+	Count        int     `json:"count"`
+	Value        int     `json:"value"`
+	CurrentCount int     `json:"currentCount"` // the count and value of the language definition - 0 when the letter has no tile
+	CurrentValue int     `json:"currentValue"`
+}
+
+// TileProposal is a tile distribution for a bag of BagSize tiles of which Blanks are blank
+type TileProposal struct {
+	Language language.Tag
+	BagSize  int
+	Blanks   int
+	Words    int // the number of words of at most TILES_RACK_LENGTH letters
+	Tiles    []TileStat
+}
+
+// ProposeTiles proposes the tile counts and values of the language of content from the words of up to
+// TILES_RACK_LENGTH letters. The letters are shared out by their frequency - every letter has at least
+// one tile - and the value of a letter is higher the fewer words and two letter words it can be placed in.
+func ProposeTiles(content CorpusContent, bagSize int, blanks int) (*TileProposal, error) {
+	corpus := content.Corpus()
+	lang := corpus.Language()
+	letterCount := corpus.LetterMax() - 1
+	if blanks < 0 || bagSize-blanks < letterCount {
+		return nil, fmt.Errorf("a bag of %d tiles with %d blanks has too few tiles for the %d letters of the %s alphabet", bagSize, blanks, letterCount, lang.String())
+	}
+	occurrences := make([]int, corpus.LetterMax())
+	words := make([]int, corpus.LetterMax())
+	twoLetter := make([]int, corpus.LetterMax())
+	totalLetters, totalWords, totalTwoLetter := 0, 0, 0
+	for _, w := range content.Words() {
+		if len(w) > TILES_RACK_LENGTH {
+			continue
+		}
+		totalWords++
+		totalLetters += len(w)
+		if len(w) == 2 {
+			totalTwoLetter++
+		}
+		var seen LetterSet
+		for _, l := range w {
+			occurrences[l]++
+			seen.Set(l)
+		}
+		for l := range seen.Letters() {
+			words[l]++
+			if len(w) == 2 {
+				twoLetter[l]++
+			}
+		}
+	}
+	if totalWords == 0 {
+		return nil, fmt.Errorf("no words of at most %d letters to propose tiles from", TILES_RACK_LENGTH)
+	}
+	share := func(n int, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(n) / float64(total)
+	}
+	proposal := &TileProposal{Language: lang, BagSize: bagSize, Blanks: blanks, Words: totalWords, Tiles: make([]TileStat, letterCount)}
+	current := make(map[string]languageTile)
+	if SupportedLanguage(lang) {
+		for _, t := range GetLanguageTiles(lang) {
+			current[t.character] = t
+		}
+	}
+	for i := range proposal.Tiles {
+		l := Letter(i + 1)
+		stat := &proposal.Tiles[i]
+		stat.Letter = l.String(corpus)
+		stat.Frequency = share(occurrences[l], totalLetters)
+		stat.WordShare = share(words[l], totalWords)
+		stat.TwoLetter = share(twoLetter[l], totalTwoLetter)
+		stat.CurrentCount = int(current[stat.Letter].count)
+		stat.CurrentValue = int(current[stat.Letter].value)
+	}
+	proposal.shareCounts(bagSize - blanks)
+	proposal.scaleValues()
+	return proposal, nil
+}
+
+// shareCounts shares out n tiles by the frequency of the letters - largest remainder first
+func (proposal *TileProposal) shareCounts(n int) {
+	tiles := proposal.Tiles
+	remainder := make([]float64, len(tiles))
+	sum := 0
+	for i := range tiles {
+		exact := tiles[i].Frequency * float64(n)
+		tiles[i].Count = max(1, int(exact))
+		remainder[i] = exact - float64(tiles[i].Count)
+		sum += tiles[i].Count
+	}
+	for ; sum < n; sum++ {
+		best := 0
+		for i := range tiles {
+			if remainder[i] > remainder[best] {
+				best = i
+			}
+		}
+		tiles[best].Count++
+		remainder[best]--
+	}
+	for ; sum > n; sum-- {
+		best := -1
+		for i := range tiles {
+			if tiles[i].Count > 1 && (best < 0 || remainder[i] < remainder[best]) {
+				best = i
+			}
+		}
+		tiles[best].Count--
+		remainder[best]++
+	}
+}
+
+// scaleValues makes the value of a letter from how hard it is to place - the bits of information of the
+// share of words holding it, and half as much for the share of two letter words - scaled to the value range
+func (proposal *TileProposal) scaleValues() {
+	hardness := make([]float64, len(proposal.Tiles))
+	for i, t := range proposal.Tiles {
+		hardness[i] = -math.Log2(max(t.WordShare, 1e-6)) - 0.5*math.Log2(max(t.TwoLetter, 1.0/64))
+	}
+	low, high := slices.Min(hardness), slices.Max(hardness)
+	for i := range proposal.Tiles {
+		value := TILES_MIN_VALUE
+		if high > low {
+			value += int(math.Round((hardness[i] - low) / (high - low) * (TILES_MAX_VALUE - TILES_MIN_VALUE)))
+		}
+		proposal.Tiles[i].Value = value
+	}
+}
+
+// LanguageDefinition returns the language definition of the proposal in the JSON format of the language
+// definition files - the other fields are those of the current definition of the language (if any)
+func (proposal *TileProposal) LanguageDefinition() ([]byte, error) {
+	var file languageFile
+	if SupportedLanguage(proposal.Language) {
+		file = getDefinition(proposal.Language).file
+	} else {
+		file = languageFile{Language: proposal.Language.String(), Corpus: "corpus_" + proposal.Language.String() + ".txt"}
+	}
+	file.Blanks = proposal.Blanks
+	file.Tiles = nil
+	data, err := json.MarshalIndent(file, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	// a tile per line as in the built in definitions
+	tiles := make([]string, len(proposal.Tiles))
+	for i, t := range proposal.Tiles {
+		letter, _ := json.Marshal(t.Letter)
+		tiles[i] = fmt.Sprintf("\t\t{\"letter\": %s, \"count\": %d, \"value\": %d}", letter, t.Count, t.Value)
+	}
+	data = bytes.Replace(data, []byte(`"tiles": null`), []byte("\"tiles\": [\n"+strings.Join(tiles, ",\n")+"\n\t]"), 1)
+	return append(data, '\n'), nil
+}
+
+// CountDifference returns the sum of the differences between the proposed and the current tile counts
+func (proposal *TileProposal) CountDifference() int {
+	difference := 0
+	for _, t := range proposal.Tiles {
+		difference += max(t.Count-t.CurrentCount, t.CurrentCount-t.Count)
+	}
+	return difference
+}
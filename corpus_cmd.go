@@ -1,30 +1,52 @@
 package main
 
 import (
+	"cmp"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 	. "wordfeud/context"
 	. "wordfeud/corpus"
+	. "wordfeud/dawg"
+	. "wordfeud/game"
 
 	"golang.org/x/text/message"
 )
 
 func corpusCmd(options *GameOptions, args []string) *CorpusResult {
-	result := new(CorpusResult)
-
+	if len(args) > 0 && args[0] == "import" {
+		return corpusImportCmd(options, args[1:])
+	}
+	if len(args) > 0 && args[0] == "diff" {
+		return corpusDiffCmd(options, args[1:])
+	}
+	if len(args) > 0 && args[0] == "tiles" {
+		return corpusTilesCmd(options, args[1:])
+	}
+	var jsonOutput bool
 	flag := flag.NewFlagSet("exkt", flag.ExitOnError)
 	registerGlobalFlags(flag)
+	BoolVarFlag(flag, &jsonOutput, []string{"json"}, false, "write the statistics as json")
 
 	flag.Parse(args)
 
-	corpus, err := NewCorpus(options.Language)
-	if err != nil {
-		fmt.Println(result.errors(), err.Error())
-		return result.result()
+	result, content := corpusStat(options)
+	if content == nil {
+		return result
 	}
-	content, err := corpus.GetLanguageContent()
-	if err != nil {
-		fmt.Println(result.errors(), err.Error())
+	if jsonOutput {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+		}
+		fmt.Fprintln(result.logger(), string(data))
 		return result.result()
 	}
 	result.Words = make([]string, len(content.Words()))
@@ -32,14 +54,379 @@ func corpusCmd(options *GameOptions, args []string) *CorpusResult {
 		result.Words[i] = string(w)
 	}
 	corpusStat := content.Stat()
+
+	p := message.NewPrinter(options.Language)
+	p.Fprintf(result.logger(), "Number of words  : %d\n", result.WordCount)
+	p.Fprintf(result.logger(), "Total words size : %d\n", result.TotalWordsSize)
+	p.Fprintf(result.logger(), "Word lengths     : %d .. %d\n", result.MinWordLength, result.MaxWordLength)
+	p.Fprintf(result.logger(), "2 letter words   : %d\n", result.TwoLetterWords)
+	p.Fprintf(result.logger(), "3 letter words   : %d\n", result.ThreeLetterWords)
+	p.Fprintf(result.logger(), "Bingo words      : %d (%d and %d letters)\n", result.BingoWords, BINGO_LENGTHS[0], BINGO_LENGTHS[1])
+	if len(corpusStat.Filtered) > 0 {
+		p.Fprintf(result.logger(), "Words removed by :\n")
+		for _, count := range corpusStat.Filtered {
+			p.Fprintf(result.logger(), "   %-15s : %d\n", count.Rule, count.Count)
+		}
+	}
+	if options.Dictionary != "" {
+		p.Fprintf(result.logger(), "Sources          : %s\n", strings.Join(result.Sources, ", "))
+		for _, sources := range slices.Sorted(maps.Keys(result.Provenance)) {
+			p.Fprintf(result.logger(), "   %-15s : %d\n", sources, result.Provenance[sources])
+		}
+	}
+	longest := 1
+	for _, count := range result.LengthCounts {
+		longest = max(longest, count)
+	}
+	p.Fprintf(result.logger(), "Word length histogram :\n")
+	for length := result.MinWordLength; length <= result.MaxWordLength; length++ {
+		count := result.LengthCounts[length]
+		p.Fprintf(result.logger(), "   %2d : %8d %s\n", length, count, strings.Repeat("#", (count*50+longest-1)/longest))
+	}
+	corpus := content.Corpus()
+	p.Fprintf(result.logger(), "Letter frequencies :\n")
+	for l := corpus.FirstLetter(); l <= corpus.LastLetter(); l++ {
+		letter := l.String(corpus)
+		p.Fprintf(result.logger(), "   %-3s : %8d %6.2f%%\n", letter, result.LetterCounts[letter], 100*float64(result.LetterCounts[letter])/float64(max(result.TotalWordsSize, 1)))
+	}
+	bigrams := slices.SortedFunc(maps.Keys(result.Bigrams), func(l string, r string) int {
+		return cmp.Or(result.Bigrams[r]-result.Bigrams[l], strings.Compare(l, r))
+	})
+	p.Fprintf(result.logger(), "Most frequent bigrams :\n")
+	for _, bigram := range bigrams[:min(len(bigrams), corpusTopBigrams)] {
+		p.Fprintf(result.logger(), "   %-6s : %8d\n", bigram, result.Bigrams[bigram])
+	}
+	return result.result()
+}
+
+const corpusTopBigrams = 20
+
+// corpusStat returns the statistics of the corpus (or dictionary) of the language of options and the content
+func corpusStat(options *GameOptions) (*CorpusResult, CorpusContent) {
+	result := new(CorpusResult)
+	var content CorpusContent
+	var err error
+	if options.Dictionary == "" {
+		var corpus Corpus
+		corpus, err = NewCorpus(options.Language)
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			return result.result(), nil
+		}
+		content, err = corpus.GetLanguageContent()
+	} else {
+		content, err = SharedDictionaryContent(options.Language, options.Dictionary)
+	}
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result(), nil
+	}
+	corpus := content.Corpus()
+	corpusStat := content.Stat()
 	result.WordCount = corpusStat.WordCount
 	result.MinWordLength = corpusStat.MinWordLength
 	result.MaxWordLength = corpusStat.MaxWordLength
 	result.TotalWordsSize = corpusStat.TotalWordsSize
+	result.Filtered = make(map[string]int)
+	for _, count := range corpusStat.Filtered {
+		result.Filtered[count.Rule] = count.Count
+	}
+	result.LengthCounts = make(map[int]int)
+	for length, count := range corpusStat.LengthCounts {
+		if count > 0 {
+			result.LengthCounts[length] = count
+		}
+	}
+	result.LetterCounts = make(map[string]int)
+	result.Bigrams = make(map[string]int)
+	for first := corpus.FirstLetter(); first <= corpus.LastLetter(); first++ {
+		result.LetterCounts[first.String(corpus)] = corpusStat.LetterCounts[first]
+		for second := corpus.FirstLetter(); second <= corpus.LastLetter(); second++ {
+			if count := corpusStat.BigramCounts[BigramIndex(corpus, first, second)]; count > 0 {
+				result.Bigrams[first.String(corpus)+second.String(corpus)] = count
+			}
+		}
+	}
+	result.TwoLetterWords = corpusStat.LengthCount(2)
+	result.ThreeLetterWords = corpusStat.LengthCount(3)
+	result.BingoWords = corpusStat.BingoCount()
+	if options.Dictionary != "" {
+		result.Sources = content.Sources()
+		result.Provenance = make(map[string]int)
+		for _, w := range content.Words() {
+			result.Provenance[strings.Join(content.Provenance(w), "+")]++
+		}
+	}
+	return result.result(), content
+}
+
+// corpusImportCmd reads the words of the source files and writes them as a corpus file
+// and the lines which are not words as a reject file next to it
+func corpusImportCmd(options *GameOptions, args []string) *CorpusResult {
+	result := new(CorpusResult)
+	var separator string
+	var members string
+	var toFileName string
+	var posColumn int
+	var lemmaColumn int
+	var frequencyColumn int
+	var glossColumn int
+	flag := flag.NewFlagSet("corpus import", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	StringVarFlag(flag, &separator, []string{"sep"}, "\t", "the field separator of the source files")
+	StringVarFlag(flag, &members, []string{"member"}, "", "the files to read in zip archives")
+	StringVarFlag(flag, &toFileName, []string{"to"}, "", "the corpus file to write - default is the corpus file of the language")
+	IntVarFlag(flag, &posColumn, []string{"pos"}, 0, "the column holding the part of speech of the words")
+	IntVarFlag(flag, &lemmaColumn, []string{"lemma"}, 0, "the column holding the lemma of the words")
+	IntVarFlag(flag, &frequencyColumn, []string{"freq"}, 0, "the column holding the frequency of the words")
+	IntVarFlag(flag, &glossColumn, []string{"gloss"}, 0, "the column holding a short explanation of the words")
+	flag.Parse(args)
+	separator = strings.ReplaceAll(separator, `\t`, "\t")
+	if flag.NArg() == 0 {
+		fmt.Fprintln(result.errors(), "Please specify one or more source files - file or file:column")
+		return result.result()
+	}
+	if toFileName == "" {
+		toFileName = GetLanguageFileName(options.Language)
+	}
+	corpus, err := NewCorpus(options.Language)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	sources := make([]ImportSource, flag.NArg())
+	for i, spec := range flag.Args() {
+		sources[i], err = ParseImportSource(spec, separator)
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			return result.result()
+		}
+		sources[i].Members = members
+		sources[i].POSColumn = posColumn
+		sources[i].LemmaColumn = lemmaColumn
+		sources[i].FrequencyColumn = frequencyColumn
+		sources[i].GlossColumn = glossColumn
+	}
+	report, err := ImportWords(corpus, sources)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	rejectFileName := strings.TrimSuffix(toFileName, path.Ext(toFileName)) + ".rejected"
+	if err = report.WriteCorpusFile(toFileName); err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	if err = report.WriteRejectFile(rejectFileName); err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	if err = report.WriteMetadataFile(toFileName); err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	if len(report.Metadata) > 0 {
+		result.MetadataFile = MetadataFileName(toFileName)
+	}
+	result.ImportedFile = toFileName
+	result.RejectFile = rejectFileName
+	result.Rejected = len(report.Rejected)
+	result.Duplicates = report.Duplicates
+	result.WordCount = len(report.Words)
 
 	p := message.NewPrinter(options.Language)
-	p.Fprintf(result.logger(), "Number of words  : %d\n", result.WordCount)
-	p.Fprintf(result.logger(), "Total words size : %d\n", result.TotalWordsSize)
-	p.Fprintf(result.logger(), "Word lengths     : %d .. %d\n", result.MinWordLength, result.MaxWordLength)
+	p.Fprintf(result.logger(), "Lines read       : %d\n", report.Lines)
+	p.Fprintf(result.logger(), "Words imported   : %d\n", result.WordCount)
+	p.Fprintf(result.logger(), "Duplicates       : %d\n", result.Duplicates)
+	p.Fprintf(result.logger(), "Lines rejected   : %d\n", result.Rejected)
+	rejected := make(map[string]int)
+	for _, reject := range report.Rejected {
+		rejected[reject.Reason]++
+	}
+	for _, reason := range slices.Sorted(maps.Keys(rejected)) {
+		p.Fprintf(result.logger(), "   %-15s : %d\n", reason, rejected[reason])
+	}
+	p.Fprintf(result.logger(), "Corpus file      : %s\n", result.ImportedFile)
+	p.Fprintf(result.logger(), "Reject file      : %s\n", result.RejectFile)
+	if result.MetadataFile != "" {
+		p.Fprintf(result.logger(), "Metadata file    : %s (%d words)\n", result.MetadataFile, len(report.Metadata))
+	}
 	return result.result()
 }
+
+// corpusDiffCmd reports the words added and removed from corpus file OLD to NEW, the removed words played
+// in the game files of the game directory and writes a patch file in the overlay file format
+func corpusDiffCmd(options *GameOptions, args []string) *CorpusResult {
+	result := new(CorpusResult)
+	var gameDirectory string
+	var patchFileName string
+	flag := flag.NewFlagSet("corpus diff", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	StringVarFlag(flag, &gameDirectory, []string{"games"}, "", "the directory of game files to search for removed words")
+	StringVarFlag(flag, &patchFileName, []string{"patch"}, "", "the patch file to write")
+	flag.Parse(args)
+	if flag.NArg() != 2 {
+		fmt.Fprintln(result.errors(), "Please specify the OLD and the NEW corpus file")
+		return result.result()
+	}
+	oldFileName, newFileName := flag.Arg(0), flag.Arg(1)
+	if gameDirectory == "" {
+		gameDirectory = options.Directory
+	}
+	if patchFileName == "" {
+		patchFileName = strings.TrimSuffix(newFileName, path.Ext(newFileName)) + ".patch"
+	}
+	corpus, err := NewCorpus(options.Language)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	oldContent, err := corpus.GetFileContent(oldFileName)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	newContent, err := corpus.GetFileContent(newFileName)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	diff := DiffContents(oldContent, newContent)
+	result.Added = wordStrings(corpus, diff.Added)
+	result.Removed = wordStrings(corpus, diff.Removed)
+	result.AddedByLength = diff.AddedByLength
+	result.RemovedByLength = diff.RemovedByLength
+	if gameDirectory != "" {
+		played, err := gameFileWords(gameDirectory)
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			return result.result()
+		}
+		result.Played = make(map[string][]string)
+		for _, w := range result.Removed {
+			if files, found := played[w]; found {
+				result.Played[w] = files
+			}
+		}
+	}
+	if err = WriteOverlayFile(patchFileName, Overlay{Added: diff.Added, Removed: diff.Removed}, corpus); err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	result.PatchFile = patchFileName
+
+	p := message.NewPrinter(options.Language)
+	p.Fprintf(result.logger(), "Words in old     : %d (%s)\n", oldContent.WordCount(), oldFileName)
+	p.Fprintf(result.logger(), "Words in new     : %d (%s)\n", newContent.WordCount(), newFileName)
+	p.Fprintf(result.logger(), "Words added      : %d\n", len(result.Added))
+	p.Fprintf(result.logger(), "Words removed    : %d\n", len(result.Removed))
+	lengths := slices.Collect(maps.Keys(diff.AddedByLength))
+	for l := range diff.RemovedByLength {
+		if _, found := diff.AddedByLength[l]; !found {
+			lengths = append(lengths, l)
+		}
+	}
+	slices.Sort(lengths)
+	for _, l := range lengths {
+		p.Fprintf(result.logger(), "   length %2d     : +%d -%d\n", l, diff.AddedByLength[l], diff.RemovedByLength[l])
+	}
+	if result.Played != nil {
+		p.Fprintf(result.logger(), "Removed words played in \"%s\" : %d\n", gameDirectory, len(result.Played))
+		for _, w := range slices.Sorted(maps.Keys(result.Played)) {
+			p.Fprintf(result.logger(), "   %-15s : %s\n", w, strings.Join(result.Played[w], ", "))
+		}
+	}
+	p.Fprintf(result.logger(), "Patch file       : %s (apply with word patch)\n", result.PatchFile)
+	return result.result()
+}
+
+// corpusTilesCmd proposes the tile counts and values of the language from the letter statistics of
+// the corpus, compares them with the tiles of the language definition and writes the language definition
+func corpusTilesCmd(options *GameOptions, args []string) *CorpusResult {
+	result := new(CorpusResult)
+	var bagSize int
+	var blanks int
+	var toFileName string
+	flag := flag.NewFlagSet("corpus tiles", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	IntVarFlag(flag, &bagSize, []string{"bag"}, 100, "the number of tiles in the bag - blanks included")
+	IntVarFlag(flag, &blanks, []string{"blanks"}, GetLanguageBlanks(options.Language), "the number of blank tiles")
+	StringVarFlag(flag, &toFileName, []string{"to"}, "", "the language definition file to write - default is to print it")
+	flag.Parse(args)
+	content, err := SharedDictionaryContent(options.Language, options.Dictionary)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	proposal, err := ProposeTiles(content, bagSize, blanks)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	definition, err := proposal.LanguageDefinition()
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	result.WordCount = content.WordCount()
+	result.Tiles = proposal.Tiles
+	result.Definition = string(definition)
+	if toFileName != "" {
+		if err = os.WriteFile(toFileName, definition, 0644); err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			return result.result()
+		}
+		result.DefinitionFile = toFileName
+	}
+
+	p := message.NewPrinter(options.Language)
+	p.Fprintf(result.logger(), "Number of words  : %d (%d of at most %d letters)\n", result.WordCount, proposal.Words, TILES_RACK_LENGTH)
+	p.Fprintf(result.logger(), "Bag              : %d tiles - %d blanks\n", bagSize, blanks)
+	p.Fprintf(result.logger(), "Letter  Frequency  Words  2-letter  Count Value  Current\n")
+	for _, t := range proposal.Tiles {
+		p.Fprintf(result.logger(), "%-6s %9.2f%% %5.1f%% %8.1f%%  %5d %5d  %3d %3d\n", t.Letter,
+			100*t.Frequency, 100*t.WordShare, 100*t.TwoLetter, t.Count, t.Value, t.CurrentCount, t.CurrentValue)
+	}
+	p.Fprintf(result.logger(), "Tile count difference from the %s definition : %d\n", options.Language.String(), proposal.CountDifference())
+	if result.DefinitionFile != "" {
+		p.Fprintf(result.logger(), "Language definition : %s\n", result.DefinitionFile)
+	} else {
+		fmt.Fprint(result.logger(), result.Definition)
+	}
+	return result.result()
+}
+
+func wordStrings(corpus Corpus, words Words) []string {
+	strs := make([]string, len(words))
+	for i, w := range words {
+		strs[i] = w.String(corpus)
+	}
+	return strs
+}
+
+// gameFileWords returns the json game files of directory (and its subdirectories) by the words of their moves
+func gameFileWords(directory string) (map[string][]string, error) {
+	words := make(map[string][]string)
+	err := filepath.WalkDir(directory, func(fileName string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(fileName) != ".json" {
+			return err
+		}
+		f, err := os.Open(fileName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, played, err := ReadGameFileJson(f)
+		if err != nil {
+			return fmt.Errorf("game file \"%s\" : %v", fileName, err)
+		}
+		for _, word := range played {
+			w := strings.ToUpper(word)
+			if files := words[w]; len(files) == 0 || files[len(files)-1] != fileName {
+				words[w] = append(files, fileName)
+			}
+		}
+		return nil
+	})
+	return words, err
+}
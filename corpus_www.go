@@ -0,0 +1,21 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	. "wordfeud/context"
+)
+
+// corpusStatWWW answers /scrabble/corpus/stat?dict=spec with the statistics of the corpus as a json CorpusResult
+func corpusStatWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	options = options.Copy()
+	if dictionary := req.URL.Query().Get("dict"); dictionary != "" {
+		options.Dictionary = dictionary
+	}
+	result, content := corpusStat(options)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if content == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(result)
+}
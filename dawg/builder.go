@@ -3,6 +3,7 @@ package dawg
 import (
 	"fmt"
 	"os"
+	. "wordfeud/context"
 	. "wordfeud/corpus"
 )
 
@@ -17,17 +18,47 @@ type dawgBuilder struct {
 	registry     Registry
 	nextNodeId   nodeID
 	nextVertexId vertexID
+	wordCount    int
+}
+
+func newDawgBuilder(corpus Corpus, options Options) *dawgBuilder {
+	dawg := &_Dawg{
+		_options: options,
+		corpus:   corpus,
+	}
+
+	builder := &dawgBuilder{
+		dawg:         dawg,
+		registry:     make(Registry),
+		nextNodeId:   1,
+		nextVertexId: 1,
+	}
+	dawg._rootNode = builder.newNode()
+	dawg._finalNode = builder.newNode()
+	dawg.nullState = &_DawgState{dawg: dawg, startNode: nil, vertices: vertices{}}
+	dawg._initialState = &_DawgState{dawg: dawg, startNode: dawg._rootNode, vertices: vertices{}}
+	builder.register(dawg._finalNode)
+	return builder
 }
 
 func (builder *dawgBuilder) addCorpus(content CorpusContent) error {
+	if err := builder.addWords(content.Words()); err != nil {
+		return err
+	}
+	builder.finish()
+	return nil
+}
+
+// addWords adds words which must be sorted and follow all words allready added
+func (builder *dawgBuilder) addWords(words Words) error {
 	dawg := builder.dawg
-	for i, w := range content.Words() {
+	for _, w := range words {
 		err := builder.addWord((w))
 		if err != nil {
 			return err
 		}
 		if DAWG_TRACE {
-			if i == 0 {
+			if builder.wordCount == 0 {
 				err = os.RemoveAll(dotDir)
 				if err != nil {
 					return err
@@ -37,15 +68,22 @@ func (builder *dawgBuilder) addCorpus(content CorpusContent) error {
 					return err
 				}
 			}
-			dawg.printDot(i, w.String(dawg.corpus))
+			dawg.printDot(builder.wordCount, w.String(dawg.corpus))
 		}
+		builder.wordCount++
+	}
+	return nil
+}
+
+func (builder *dawgBuilder) finish() {
+	dawg := builder.dawg
+	if dawg._rootNode.hasVertices() {
+		builder.replaceOrRegister(dawg._rootNode)
 	}
-	builder.replaceOrRegister(dawg._rootNode)
 	if DAWG_TRACE {
-		dawg.printDot(content.WordCount(), "FINAL")
+		dawg.printDot(builder.wordCount, "FINAL")
 		dawg.print()
 	}
-	return nil
 }
 
 func (builder *dawgBuilder) addWord(word Word) error {
@@ -53,7 +91,7 @@ func (builder *dawgBuilder) addWord(word Word) error {
 	if DAWG_TRACE {
 		dawg.trace("\n\nAddWord \"%s\"\n\n", word.String(dawg.corpus))
 	}
-	prefixState := dawg.FindPrefix(word)
+	prefixState := dawg.findPrefix(word)
 	prefixNode := prefixState.lastNode()
 	if prefixNode.hasVertices() {
 		builder.replaceOrRegister(prefixNode)
@@ -101,7 +139,7 @@ func (builder *dawgBuilder) replaceOrRegister(node *node) {
 		if lastVertex.destination.hasVertices() {
 			hasVertices = "has vertices"
 		}
-		fmt.Printf("lastvertex#%v('%c').destination: node#%v %s\n", lastVertex.id, dawg.corpus.LetterToRune(lastVertex.letter), node.id, hasVertices)
+		fmt.Printf("lastvertex#%v('%s').destination: node#%v %s\n", lastVertex.id, dawg.corpus.LetterToString(lastVertex.letter), node.id, hasVertices)
 	}
 
 	if lastVertex.destination.hasVertices() {
@@ -117,7 +155,7 @@ func (builder *dawgBuilder) replaceOrRegister(node *node) {
 		lastVertex.destination = registryNode
 
 		if DAWG_TRACE {
-			fmt.Printf("lastvertex#%v('%c').destination <= registry node#%v\n", lastVertex.id, dawg.corpus.LetterToRune(lastVertex.letter), registryNode.id)
+			fmt.Printf("lastvertex#%v('%s').destination <= registry node#%v\n", lastVertex.id, dawg.corpus.LetterToString(lastVertex.letter), registryNode.id)
 		}
 
 	} else {
@@ -128,14 +166,14 @@ func (builder *dawgBuilder) replaceOrRegister(node *node) {
 func (builder *dawgBuilder) addVertex(node *node, letter Letter, destination *node, final bool) *vertex {
 	dawg := builder.dawg
 	if DAWG_TRACE {
-		fmt.Printf("addVertex node#%v letter:'%c' destination:node%v final:%v\n", node.id, dawg.corpus.LetterToRune(letter), destination.id, final)
+		fmt.Printf("addVertex node#%v letter:'%s' destination:node%v final:%v\n", node.id, dawg.corpus.LetterToString(letter), destination.id, final)
 		dawg.printNode(node)
 	}
 	if node.vertexLetters.Test(letter) {
-		panic(fmt.Sprintf("node:%v trying to add vertex with an allready present letter ('%c') (node.addVertex)", node.id, dawg.corpus.LetterToRune(letter)))
+		panic(fmt.Sprintf("node:%v trying to add vertex with an allready present letter ('%s') (node.addVertex)", node.id, dawg.corpus.LetterToString(letter)))
 	}
 	if node.registered {
-		panic(fmt.Sprintf("node:%v trying to add vertex with letter ('%c') to registered node (node.addVertex)", node.id, dawg.corpus.LetterToRune(letter)))
+		panic(fmt.Sprintf("node:%v trying to add vertex with letter ('%s') to registered node (node.addVertex)", node.id, dawg.corpus.LetterToString(letter)))
 	}
 	vertex := builder.newVertex(letter, destination, final)
 	node.vertices = append(node.vertices, vertex)
@@ -1,8 +1,11 @@
 package dawg
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"iter"
 	"os"
 	. "wordfeud/context"
 	. "wordfeud/corpus"
@@ -19,8 +22,12 @@ type Dawg interface {
 	Match(Word) bool
 	FindPrefix(Word) DawgState
 	InitialState() DawgState
-	rootNode() *node
+	Words() iter.Seq[Word]
+	WordsWithPrefix(Word) iter.Seq[Word]
+	WordsOfLength(int) iter.Seq[Word]
+	Hooks(Word) (front LetterSet, back LetterSet)
 	options() *Options
+	statistics() DawgStat
 }
 
 type _Dawg struct {
@@ -28,43 +35,65 @@ type _Dawg struct {
 	_options      Options
 	_rootNode     *node
 	_finalNode    *node
-	_initialState DawgState
-	nullState     DawgState
+	_initialState *_DawgState
+	nullState     *_DawgState
 }
 
+// NewDawg returns the dawg for content.
+// If content was read from a file and a compiled dawg file for the same content is
+// present next to it, the compiled dawg is loaded - otherwise the dawg is built.
+// An overlay file next to the corpus file is added to the dawg.
 func NewDawg(content CorpusContent, options Options) (Dawg, error) {
-	corpus := content.Corpus()
+	dawg, err := newDawg(content, options)
+	if err != nil {
+		return nil, err
+	}
+	return withOverlay(dawg, content, false)
+}
 
-	dawg := &_Dawg{
-		_options: options,
-		corpus:   corpus,
+func newDawg(content CorpusContent, options Options) (Dawg, error) {
+	if fileName := content.FileName(); fileName != "" {
+		compiledFileName := CompiledDawgFileName(fileName)
+		dawg, err := ReadDawgFile(compiledFileName, content, options)
+		if err == nil {
+			if options.Verbose {
+				fmt.Printf("loaded compiled dawg file \"%s\"\n", compiledFileName)
+			}
+			return dawg, nil
+		}
+		if options.Verbose && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Printf("cannot use compiled dawg file - building dawg : %s\n", err.Error())
+		}
 	}
+	return BuildDawg(content, options)
+}
 
-	builder := dawgBuilder{
-		dawg:         dawg,
-		registry:     make(Registry),
-		nextNodeId:   1,
-		nextVertexId: 1,
+// BuildDawg builds the dawg from the words of content and returns it in the packed form
+func BuildDawg(content CorpusContent, options Options) (Dawg, error) {
+	dawg, err := buildDawg(content, options)
+	if err != nil {
+		return nil, err
 	}
-	dawg._rootNode = builder.newNode()
-	dawg._finalNode = builder.newNode()
-	dawg.nullState = &_DawgState{dawg: dawg, startNode: nil, vertices: vertices{}}
-	dawg._initialState = &_DawgState{dawg: dawg, startNode: dawg._rootNode, vertices: vertices{}}
-	builder.register(dawg._finalNode)
+	return packDawg(dawg)
+}
 
+func buildDawg(content CorpusContent, options Options) (*_Dawg, error) {
+	builder := newDawgBuilder(content.Corpus(), options)
 	err := builder.addCorpus(content)
 	if err != nil {
 		return nil, err
 	}
-	return dawg, nil
+	return builder.dawg, nil
 }
 
 func (dawg *_Dawg) options() *Options {
 	return &dawg._options
 }
 
-func (dawg *_Dawg) rootNode() *node {
-	return dawg._rootNode
+func (dawg *_Dawg) statistics() DawgStat {
+	var statistics DawgStat
+	statistics.update(make(map[*node]bool), dawg._rootNode)
+	return statistics
 }
 
 func (dawg *_Dawg) InitialState() DawgState {
@@ -101,14 +130,18 @@ func (dawg *_Dawg) Match(word Word) bool {
 }
 
 func (dawg *_Dawg) FindPrefix(word Word) DawgState {
-	state := dawg.InitialState()
+	return dawg.findPrefix(word)
+}
+
+func (dawg *_Dawg) findPrefix(word Word) *_DawgState {
+	state := dawg._initialState
 	if DAWG_TRACE {
 		fmt.Printf("FindPrefix \"%s\" : \n", word.String(dawg.corpus))
 		state.Print()
 	}
 
 	for _, l := range word {
-		nextState := state.Transition(l)
+		nextState := state.transition(l)
 		if !nextState.Valid() {
 			break
 		}
@@ -132,7 +165,7 @@ func (dawg *_Dawg) fprintfNode(f io.Writer, node *node) {
 		if v.destination != nil {
 			dest = fmt.Sprintf("node#%v %s", v.destination.id, v.destination.vertexLetters.String(dawg.corpus))
 		}
-		fmt.Fprintf(f, "  +-- [%v] vertex#%v  letter:'%c' final:%v destination:%s \n", i, v.id, dawg.corpus.LetterToRune(v.letter), v.final, dest)
+		fmt.Fprintf(f, "  +-- [%v] vertex#%v  letter:'%s' final:%v destination:%s \n", i, v.id, dawg.corpus.LetterToString(v.letter), v.final, dest)
 	}
 }
 
@@ -200,9 +233,9 @@ func (dawg *_Dawg) printfDotRecurse(f io.Writer, printedNodes map[*node]bool, pr
 		if v.destination != nil {
 			if printVertices {
 				if v.final {
-					fmt.Fprintf(f, "%d -> %d [label=\" %c\" arrowhead=\"diamond\"]\n", node.id, v.destination.id, dawg.corpus.LetterToRune(v.letter))
+					fmt.Fprintf(f, "%d -> %d [label=\" %s\" arrowhead=\"diamond\"]\n", node.id, v.destination.id, dawg.corpus.LetterToString(v.letter))
 				} else {
-					fmt.Fprintf(f, "%d -> %d [label=\" %c\"]\n", node.id, v.destination.id, dawg.corpus.LetterToRune(v.letter))
+					fmt.Fprintf(f, "%d -> %d [label=\" %s\"]\n", node.id, v.destination.id, dawg.corpus.LetterToString(v.letter))
 				}
 			}
 			dawg.printfDotRecurse(f, printedNodes, printVertices, v.destination)
@@ -6,13 +6,10 @@ type DawgStat struct {
 }
 
 func DawgStatistics(dawg Dawg) DawgStat {
-	var statistics DawgStat
-	var nodesVisited = make(map[*node]bool)
-	statistics.update(dawg, nodesVisited, dawg.rootNode())
-	return statistics
+	return dawg.statistics()
 }
 
-func (statistics *DawgStat) update(dawg Dawg, nodesVisited map[*node]bool, node *node) {
+func (statistics *DawgStat) update(nodesVisited map[*node]bool, node *node) {
 	if node == nil {
 		return
 	}
@@ -23,6 +20,6 @@ func (statistics *DawgStat) update(dawg Dawg, nodesVisited map[*node]bool, node
 	statistics.NodeCount++
 	statistics.VertexCount += len(node.vertices)
 	for _, v := range node.vertices {
-		statistics.update(dawg, nodesVisited, v.destination)
+		statistics.update(nodesVisited, v.destination)
 	}
 }
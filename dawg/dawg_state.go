@@ -3,6 +3,7 @@ package dawg
 import (
 	"fmt"
 	"io"
+	"iter"
 	"os"
 	"slices"
 	. "wordfeud/corpus"
@@ -16,11 +17,11 @@ type DawgState interface {
 	WordLength() int
 	Transition(Letter) DawgState
 	Transitions(Word) DawgState
+	Letters() LetterSet
 	ValidContinuations(suffixes ...Word) LetterSet
+	Words() iter.Seq[Word]
 	Print(args ...string)
 	FprintState(f io.Writer, args ...string)
-	lastNode() *node
-	lastVertex() *vertex
 }
 
 type _DawgState struct {
@@ -46,25 +47,29 @@ func (state *_DawgState) Final() bool {
 }
 
 func (state *_DawgState) Transition(letter Letter) DawgState {
+	return state.transition(letter)
+}
+
+func (state *_DawgState) transition(letter Letter) *_DawgState {
 	dawg := state.dawg
 	if !state.Valid() {
 		return dawg.nullState
 	}
 	if DAWG_TRACE {
-		fmt.Printf("Transition '%c' on state: \n", dawg.corpus.LetterToRune(letter))
+		fmt.Printf("Transition '%s' on state: \n", dawg.corpus.LetterToString(letter))
 		state.Print()
 	}
 
 	if state.startNode == nil {
 		if DAWG_TRACE {
-			fmt.Printf("Transition '%c' on null state => dawg.nullState\n", dawg.corpus.LetterToRune(letter))
+			fmt.Printf("Transition '%s' on null state => dawg.nullState\n", dawg.corpus.LetterToString(letter))
 		}
 		return dawg.nullState
 	}
 	node := state.lastNode()
 	if node == nil {
 		if DAWG_TRACE {
-			fmt.Printf("Transition '%c' on nil destination => dawg.nullState\n", dawg.corpus.LetterToRune(letter))
+			fmt.Printf("Transition '%s' on nil destination => dawg.nullState\n", dawg.corpus.LetterToString(letter))
 		}
 		return dawg.nullState
 	}
@@ -72,7 +77,7 @@ func (state *_DawgState) Transition(letter Letter) DawgState {
 	_, v := node.findVertex(letter)
 	if v == nil {
 		if DAWG_TRACE {
-			fmt.Printf("vertext for letter '%c' not found in node#%v  => dawg.nullState\n", dawg.corpus.LetterToRune(letter), node.id)
+			fmt.Printf("vertext for letter '%s' not found in node#%v  => dawg.nullState\n", dawg.corpus.LetterToString(letter), node.id)
 			dawg.printNode(node)
 		}
 		return dawg.nullState
@@ -85,8 +90,8 @@ func (state *_DawgState) Transition(letter Letter) DawgState {
 	}
 
 	if DAWG_TRACE {
-		fmt.Printf("Transition '%c' in node#%v  => vertex#%v node#%v final:%v word:\"%s\"\n",
-			dawg.corpus.LetterToRune(letter), node.id, v.id, v.destination.id, v.final, transitionState.Word().String(dawg.corpus))
+		fmt.Printf("Transition '%s' in node#%v  => vertex#%v node#%v final:%v word:\"%s\"\n",
+			dawg.corpus.LetterToString(letter), node.id, v.id, v.destination.id, v.final, transitionState.Word().String(dawg.corpus))
 		transitionState.Print()
 	}
 
@@ -101,9 +106,15 @@ func (state *_DawgState) Transitions(word Word) DawgState {
 	if len(word) == 0 {
 		return state
 	}
-	transitionState := state.Transition(word[0])
-	transitionState = transitionState.Transitions(word[1:])
-	return transitionState
+	transitionState := state.transition(word[0])
+	return transitionState.Transitions(word[1:])
+}
+
+func (state *_DawgState) Letters() LetterSet {
+	if !state.Valid() {
+		return NullLetterSet
+	}
+	return state.lastNode().vertexLetters
 }
 
 func (state *_DawgState) ValidContinuations(suffixes ...Word) LetterSet {
@@ -201,6 +212,6 @@ func (state *_DawgState) FprintState(f io.Writer, args ...string) {
 		if v.destination != nil {
 			dest = fmt.Sprintf("node#%v %s", v.destination.id, v.destination.vertexLetters.String(dawg.corpus))
 		}
-		fmt.Fprintf(f, "%s  +-- [%v] vertex#%v  letter:'%c' final:%v destination:%s \n", indent, i, v.id, corpus.LetterToRune(v.letter), v.final, dest)
+		fmt.Fprintf(f, "%s  +-- [%v] vertex#%v  letter:'%s' final:%v destination:%s \n", indent, i, v.id, corpus.LetterToString(v.letter), v.final, dest)
 	}
 }
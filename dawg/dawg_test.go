@@ -2,10 +2,15 @@ package dawg
 
 import (
 	"fmt"
+	"iter"
 	"os"
+	"path"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"testing"
+	"unicode/utf8"
 	. "wordfeud/context"
 	. "wordfeud/corpus"
 
@@ -30,6 +35,189 @@ func Test_DawgBasic(t *testing.T) {
 	testDawgContent(t, language.Danish, content)
 }
 
+func Test_DawgHooks(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_DawgHooks() failed to create corpus : %v", err)
+		return
+	}
+	content, err := NewTestCorpusFromContent(corpus, []string{"al", "ale", "als", "hal", "hale", "sal", "sale", "salg"})
+	if err != nil {
+		t.Errorf("Test_DawgHooks() failed to create corpus content : %v", err)
+		return
+	}
+	built, err := buildDawg(content, Options{})
+	if err != nil {
+		t.Errorf("Test_DawgHooks() failed to build dawg : %v", err)
+		return
+	}
+	packed, err := packDawg(built)
+	if err != nil {
+		t.Errorf("Test_DawgHooks() failed to pack dawg : %v", err)
+		return
+	}
+	tests := []struct{ word, front, back string }{
+		{"AL", "{H,S}", "{E,S}"},
+		{"SAL", "{}", "{E,G}"},
+		{"ALE", "{H,S}", "{}"},
+		{"XY", "{}", "{}"},
+	}
+	for _, dawg := range []Dawg{built, packed} {
+		for _, test := range tests {
+			word, _ := corpus.ParseWord(test.word)
+			front, back := dawg.Hooks(word)
+			if front.String(corpus) != test.front || back.String(corpus) != test.back {
+				t.Errorf("hooks of \"%s\" are %s %s - expected %s %s", test.word, front.String(corpus), back.String(corpus), test.front, test.back)
+			}
+		}
+	}
+}
+
+func Test_OverlayDawg(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_OverlayDawg() failed to create corpus : %v", err)
+		return
+	}
+	content, err := NewTestCorpusFromContent(corpus, []string{"al", "ale", "als", "hal", "hale", "sal"})
+	if err != nil {
+		t.Errorf("Test_OverlayDawg() failed to create corpus content : %v", err)
+		return
+	}
+	base, err := BuildDawg(content, Options{})
+	if err != nil {
+		t.Errorf("Test_OverlayDawg() failed to create dawg : %v", err)
+		return
+	}
+	parse := func(words ...string) Words {
+		out := make(Words, len(words))
+		for i, w := range words {
+			out[i], _ = corpus.ParseWord(w)
+		}
+		return out
+	}
+	fileName := t.TempDir() + "/corpus.overlay"
+	if err = WriteOverlayFile(fileName, Overlay{Added: parse("SALG", "ØL"), Removed: parse("HAL")}, corpus); err != nil {
+		t.Errorf("Test_OverlayDawg() failed to write overlay file : %v", err)
+		return
+	}
+	overlay, err := ReadOverlayFile(fileName, corpus)
+	if err != nil {
+		t.Errorf("Test_OverlayDawg() failed to read overlay file : %v", err)
+		return
+	}
+	dawg := NewOverlayDawg(base, overlay)
+	if err := dawg.Add(parse("SALE")[0]); err != nil {
+		t.Errorf("Test_OverlayDawg() failed to add SALE : %v", err)
+	}
+	if err := dawg.Remove(parse("ØL")[0]); err != nil {
+		t.Errorf("Test_OverlayDawg() failed to remove ØL : %v", err)
+	}
+	invalidWords := Words{{}, parse("A")[0], {NoLetter, NoLetter}}
+	for _, word := range invalidWords {
+		if dawg.Add(word) == nil || dawg.Remove(word) == nil {
+			t.Errorf("Test_OverlayDawg() the invalid word %v was added or removed", word)
+		}
+	}
+	expected := "AL ALE ALS HALE SAL SALE SALG"
+	words := make([]string, 0)
+	for w := range dawg.Words() {
+		words = append(words, w.String(corpus))
+	}
+	if strings.Join(words, " ") != expected {
+		t.Errorf("overlay dawg words are %v - expected %s", words, expected)
+	}
+	if dawg.Match(parse("HAL")[0]) || !dawg.Match(parse("SALG")[0]) {
+		t.Errorf("overlay dawg matches removed word HAL or does not match added word SALG")
+	}
+	if back := dawg.Transitions(parse("SAL")[0]).ValidContinuations(); back.String(corpus) != "{E,G}" {
+		t.Errorf("overlay dawg back hooks of SAL are %s - expected {E,G}", back.String(corpus))
+	}
+	if front, _ := dawg.Hooks(parse("AL")[0]); front.String(corpus) != "{S}" {
+		t.Errorf("overlay dawg front hooks of AL are %s - expected {S}", front.String(corpus))
+	}
+	folded, foldedWords, err := FoldOverlay(dawg)
+	if err != nil {
+		t.Errorf("Test_OverlayDawg() failed to fold overlay : %v", err)
+		return
+	}
+	if len(foldedWords) != len(words) || iterCount(folded.Words()) != len(words) {
+		t.Errorf("folded dawg has %d words - expected %d", iterCount(folded.Words()), len(words))
+	}
+	g, err := NewGaddag(content, Options{})
+	if err != nil {
+		t.Errorf("Test_OverlayDawg() failed to create gaddag : %v", err)
+		return
+	}
+	gaddag := newGaddagOverlay(g, overlay)
+	salg := parse("SALG")[0]
+	for i := 1; i <= len(salg); i++ {
+		if !gaddag.Match(gaddagString(salg, i)) {
+			t.Errorf("gaddag overlay does not match \"%s\"", gaddagString(salg, i).String(corpus))
+		}
+	}
+	if gaddag.Match(gaddagString(parse("HAL")[0], 1)) {
+		t.Errorf("gaddag overlay matches removed word HAL")
+	}
+}
+
+func Test_PackedState(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_PackedState() failed to create corpus : %v", err)
+		return
+	}
+	long := strings.Repeat("ab", packedWordMax) + "e"
+	content, err := NewTestCorpusFromContent(corpus, []string{"abe", "abed", long})
+	if err != nil {
+		t.Errorf("Test_PackedState() failed to create corpus content : %v", err)
+		return
+	}
+	built, err := buildDawg(content, Options{})
+	if err != nil {
+		t.Errorf("Test_PackedState() failed to build dawg : %v", err)
+		return
+	}
+	packed, err := packDawg(built)
+	if err != nil {
+		t.Errorf("Test_PackedState() failed to pack dawg : %v", err)
+		return
+	}
+	for _, w := range []string{"abed", long} {
+		word, _ := corpus.ParseWord(w)
+		state := packed.InitialState()
+		for _, l := range word {
+			state = state.Transition(l)
+		}
+		if !state.Final() || !state.Word().Equal(word) || state.WordLength() != len(word) {
+			t.Errorf("Test_PackedState() the state of %s has the word %s", w, state.Word().String(corpus))
+		}
+		if state := packed.Transitions(word); !state.Final() || !state.Word().Equal(word) {
+			t.Errorf("Test_PackedState() the transitions of %s end in the word %s", w, state.Word().String(corpus))
+		}
+	}
+	// the states branching from the same state keep their own words
+	abe, _ := corpus.ParseWord("abe")
+	state := packed.Transitions(abe[:2])
+	e, b := state.Transition(abe[2]), state.Transition(abe[0])
+	if e.Word().String(corpus) != "ABE" || b.Word().String(corpus) != "ABA" || e.Final() == b.Final() {
+		t.Errorf("Test_PackedState() the states branching from AB have the words %s and %s", e.Word().String(corpus), b.Word().String(corpus))
+	}
+
+	// the initial state and each transition allocate the state they return and nothing else - a failed transition nothing
+	word, _ := corpus.ParseWord("abed")
+	allocs := testing.AllocsPerRun(100, func() {
+		state := packed.InitialState()
+		for _, l := range word {
+			state = state.Transition(l)
+		}
+		state.Transition(word[0])
+	})
+	if allocs > float64(1+len(word)) {
+		t.Errorf("Test_PackedState() %v allocations walking %d letters", allocs, len(word))
+	}
+}
+
 func Test_DawgPartialDK(t *testing.T) {
 	fmt.Println(os.Getwd())
 	testDawgLanguageFile(t, language.Danish, "data_test/dk_partial.txt")
@@ -39,6 +227,244 @@ func Test_DawgCompleteDK(t *testing.T) {
 	testDawgLanguage(t, language.Danish)
 }
 
+func Test_DawgCompiledPartialDK(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_DawgCompiledPartialDK() failed to create corpus : %v", err)
+		return
+	}
+	content, err := corpus.GetFileContent("../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_DawgCompiledPartialDK() failed to create corpus content : %v", err)
+		return
+	}
+	dawg, err := BuildDawg(content, Options{})
+	if err != nil {
+		t.Errorf("Test_DawgCompiledPartialDK() failed to create dawg : %v", err)
+		return
+	}
+	fileName := t.TempDir() + "/dk_partial.dawg"
+	if err = WriteDawgFile(fileName, dawg, content); err != nil {
+		t.Errorf("Test_DawgCompiledPartialDK() failed to write compiled dawg : %v", err)
+		return
+	}
+	compiled, err := ReadDawgFile(fileName, content, Options{})
+	if err != nil {
+		t.Errorf("Test_DawgCompiledPartialDK() failed to read compiled dawg : %v", err)
+		return
+	}
+	if DawgStatistics(compiled) != DawgStatistics(dawg) {
+		t.Errorf("compiled dawg statistics %+v differs from built dawg %+v", DawgStatistics(compiled), DawgStatistics(dawg))
+	}
+	testDawg(t, compiled, content)
+
+	other, err := NewTestCorpusFromContent(corpus, []string{"abe", "bil"})
+	if err != nil {
+		t.Errorf("Test_DawgCompiledPartialDK() failed to create corpus content : %v", err)
+		return
+	}
+	if _, err = ReadDawgFile(fileName, other, Options{}); err == nil {
+		t.Errorf("compiled dawg file was accepted for a different corpus content")
+	}
+}
+
+func Test_SharedDawg(t *testing.T) {
+	corpus, err := SharedCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_SharedDawg() failed to get shared corpus : %v", err)
+		return
+	}
+	content, err := SharedFileContent(corpus, "../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_SharedDawg() failed to get shared corpus content : %v", err)
+		return
+	}
+	dawg, err := SharedDawg(content, Options{})
+	if err != nil {
+		t.Errorf("Test_SharedDawg() failed to get shared dawg : %v", err)
+		return
+	}
+	other, err := SharedDawg(content, Options{})
+	if err != nil || other != dawg {
+		t.Errorf("Test_SharedDawg() SharedDawg returned different instances for the same content")
+	}
+
+	// a failure is not shared - the next call builds the dawg
+	failing := func(CorpusContent, Options) (Dawg, error) { return nil, fmt.Errorf("no dawg") }
+	if _, err := sharedDawg(content, Options{}, true, failing); err == nil {
+		t.Errorf("Test_SharedDawg() the failure of building a gaddag was not returned")
+	}
+	if gaddag, err := sharedDawg(content, Options{}, true, NewGaddag); err != nil || gaddag == nil {
+		t.Errorf("Test_SharedDawg() the failure of building a gaddag was shared : %v", err)
+	}
+
+	// an overlay written next to the corpus file is picked up by the next call
+	fileName := path.Join(t.TempDir(), "dk_partial.txt")
+	data, err := os.ReadFile("../data_test/dk_partial.txt")
+	if err == nil {
+		err = os.WriteFile(fileName, data, 0644)
+	}
+	if err != nil {
+		t.Errorf("Test_SharedDawg() failed to copy the corpus file : %v", err)
+		return
+	}
+	copied, err := SharedFileContent(corpus, fileName)
+	if err != nil {
+		t.Errorf("Test_SharedDawg() failed to get shared corpus content of the copy : %v", err)
+		return
+	}
+	word, _ := corpus.ParseWord("XYZXYZ")
+	if dawg, err := SharedDawg(copied, Options{}); err != nil || dawg.Match(word) {
+		t.Errorf("Test_SharedDawg() the dawg of the copy matches %s : %v", word.String(corpus), err)
+	}
+	if err := WriteOverlayFile(OverlayFileName(fileName), Overlay{Added: Words{word}}, corpus); err != nil {
+		t.Errorf("Test_SharedDawg() failed to write the overlay : %v", err)
+		return
+	}
+	if dawg, err := SharedDawg(copied, Options{}); err != nil || !dawg.Match(word) {
+		t.Errorf("Test_SharedDawg() the overlay adding %s was not picked up : %v", word.String(corpus), err)
+	}
+	if dawg, err := SharedDawg(content, Options{}); err != nil || dawg.Match(word) {
+		t.Errorf("Test_SharedDawg() the overlay of the copy was applied to the same content of another file : %v", err)
+	}
+}
+
+func Test_GaddagPartialDK(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_GaddagPartialDK() failed to create corpus : %v", err)
+		return
+	}
+	content, err := corpus.GetFileContent("../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_GaddagPartialDK() failed to create corpus content : %v", err)
+		return
+	}
+	gaddag, err := NewGaddag(content, Options{})
+	if err != nil {
+		t.Errorf("Test_GaddagPartialDK() failed to create gaddag : %v", err)
+		return
+	}
+	for _, w := range content.Words() {
+		for i := 1; i <= len(w); i++ {
+			if s := gaddagString(w, i); !gaddag.Match(s) {
+				t.Errorf("gaddag does not match \"%s\" for word \"%s\"", s.String(corpus), w.String(corpus))
+				return
+			}
+		}
+	}
+	// every path of the gaddag must spell a word of the corpus
+	allWords := make(map[string]bool)
+	for _, w := range content.Words() {
+		allWords[w.String(corpus)] = true
+	}
+	paths, expectedPaths := 0, 0
+	for _, w := range content.Words() {
+		expectedPaths += len(w)
+	}
+	var verify func(state DawgState)
+	verify = func(state DawgState) {
+		for l := Letter(0); int(l) < corpus.LetterMax(); l++ {
+			next := state.Transition(l)
+			if !next.Valid() {
+				continue
+			}
+			if next.Final() {
+				path := next.Word()
+				i := slices.Index(path, GaddagSeparator)
+				if i < 0 {
+					i = len(path)
+				}
+				word := slices.Clone(path[:i])
+				slices.Reverse(word)
+				if i < len(path) {
+					word = append(word, path[i+1:]...)
+				}
+				if !allWords[word.String(corpus)] {
+					t.Errorf("gaddag path \"%s\" is not a word of the corpus", path.String(corpus))
+				}
+				paths++
+			}
+			verify(next)
+		}
+	}
+	verify(gaddag.InitialState())
+	if paths != expectedPaths {
+		t.Errorf("gaddag has %d paths - expected %d", paths, expectedPaths)
+	}
+}
+
+func Test_FindWords(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_FindWords() failed to create corpus : %v", err)
+		return
+	}
+	content, err := corpus.GetFileContent("../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_FindWords() failed to create corpus content : %v", err)
+		return
+	}
+	dawg, err := BuildDawg(content, Options{})
+	if err != nil {
+		t.Errorf("Test_FindWords() failed to create dawg : %v", err)
+		return
+	}
+	queries := []WordQuery{
+		{Pattern: "A??E"},
+		{Pattern: "*ER"},
+		{Pattern: "A[BC]*S"},
+		{Pattern: "a[^e]?"},
+		{Pattern: "???????", Contains: "EE"},
+		{Contains: "ÆØ", MinLength: 7, MaxLength: 7},
+		{Pattern: "*NING*", MaxLength: 9},
+	}
+	for _, query := range queries {
+		expr := strings.NewReplacer("?", ".", "*", ".*").Replace(strings.ToUpper(query.Pattern))
+		re := regexp.MustCompile("^" + expr + "$")
+		expected := make([]string, 0)
+		for _, w := range content.Words() {
+			s := w.String(corpus)
+			if !re.MatchString(s) || !containsLetters(s, strings.ToUpper(query.Contains)) {
+				continue
+			}
+			n := utf8.RuneCountInString(s)
+			if (query.MinLength > 0 && n < query.MinLength) || (query.MaxLength > 0 && n > query.MaxLength) {
+				continue
+			}
+			expected = append(expected, s)
+		}
+		words, err := FindWords(dawg, query)
+		if err != nil {
+			t.Errorf("FindWords(%+v) failed : %v", query, err)
+			continue
+		}
+		found := make([]string, 0)
+		for w := range words {
+			found = append(found, w.String(corpus))
+		}
+		if !slices.Equal(found, expected) {
+			t.Errorf("FindWords(%+v) found %d words - expected %d", query, len(found), len(expected))
+		}
+	}
+	for _, pattern := range []string{"A[BC", "A1"} {
+		if _, err := FindWords(dawg, WordQuery{Pattern: pattern}); err == nil {
+			t.Errorf("FindWords accepted invalid pattern \"%s\"", pattern)
+		}
+	}
+}
+
+func containsLetters(word string, letters string) bool {
+	for _, r := range letters {
+		i := strings.IndexRune(word, r)
+		if i < 0 {
+			return false
+		}
+		word = word[:i] + word[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
 func NewTestCorpusFromContent(corpus Corpus, corpusContent []string) (CorpusContent, error) {
 	content := make([]string, len(corpusContent))
 	for i, w := range corpusContent {
@@ -86,17 +512,71 @@ func testDawgLanguageFile(t *testing.T, language language.Tag, fileName string)
 }
 
 func testDawgCorpusContent(t *testing.T, content CorpusContent) {
-	dawg, err := NewDawg(content, Options{Verbose: false, Debug: 0})
+	built, err := buildDawg(content, Options{Verbose: false, Debug: 0})
 	if err != nil {
-		t.Errorf("testDawgContent() failed to create dawg : %v", err)
+		t.Errorf("testDawgContent() failed to build dawg : %v", err)
 		return
 	}
-	testDawg(t, dawg, content)
+	testDawg(t, built, content)
+	packed, err := packDawg(built)
+	if err != nil {
+		t.Errorf("testDawgContent() failed to pack dawg : %v", err)
+		return
+	}
+	if DawgStatistics(packed) != DawgStatistics(built) {
+		t.Errorf("packed dawg statistics %+v differs from built dawg %+v", DawgStatistics(packed), DawgStatistics(built))
+	}
+	testDawg(t, packed, content)
 }
 
 func testDawg(t *testing.T, dawg Dawg, content CorpusContent) {
 	fmt.Print("\n\n")
 	verifyDawgCoverage(t, dawg, content)
+	verifyDawgWords(t, dawg, content)
+}
+
+func verifyDawgWords(t *testing.T, dawg Dawg, content CorpusContent) {
+	corpus := dawg.Corpus()
+	words := content.Words()
+	i := 0
+	for w := range dawg.Words() {
+		if i >= len(words) || !w.Equal(words[i]) {
+			t.Errorf("dawg word #%d \"%s\" is not the corpus word in collation order", i, w.String(corpus))
+			return
+		}
+		i++
+	}
+	if i != len(words) {
+		t.Errorf("dawg enumerated %d words which is not equal to corpus wordcount of %d", i, len(words))
+	}
+	if len(words) == 0 {
+		return
+	}
+	prefix := words[len(words)/2][:1]
+	length := len(words[len(words)/2])
+	prefixCount, lengthCount := 0, 0
+	for _, w := range words {
+		if len(w) >= len(prefix) && w[:len(prefix)].Equal(prefix) {
+			prefixCount++
+		}
+		if len(w) == length {
+			lengthCount++
+		}
+	}
+	if n := iterCount(dawg.WordsWithPrefix(prefix)); n != prefixCount {
+		t.Errorf("dawg has %d words with prefix \"%s\" - expected %d", n, prefix.String(corpus), prefixCount)
+	}
+	if n := iterCount(dawg.WordsOfLength(length)); n != lengthCount {
+		t.Errorf("dawg has %d words of length %d - expected %d", n, length, lengthCount)
+	}
+}
+
+func iterCount(words iter.Seq[Word]) int {
+	n := 0
+	for range words {
+		n++
+	}
+	return n
 }
 
 func verifyDawgCoverage(t *testing.T, dawg Dawg, content CorpusContent) {
@@ -115,12 +595,11 @@ func verifyCorpusMatches(t *testing.T, dawg Dawg, content CorpusContent) {
 			fmt.Printf("\nverifyCorpusMatches: %d \"%s\n\n", i, w.String(corpus))
 		}
 		s := dawg.FindPrefix(w)
-		v := s.lastVertex()
-		if v == nil {
+		if s.WordLength() != len(w) {
 			t.Errorf("corpus word #%v \"%s\" not matched by dawg", i, w.String(corpus))
 			return
 		}
-		if !v.final {
+		if !s.Final() {
 			t.Errorf("corpus word #%v \"%s\" is matched by dawg but does not end in final state", i, w.String(corpus))
 			return
 		}
@@ -152,13 +631,11 @@ func verifyMatchesInCorpus(t *testing.T, dawg Dawg, content CorpusContent) {
 func verifyMatchesInCorpusRecurse(t *testing.T, dawg Dawg, content CorpusContent, allWords map[string]bool, state DawgState) int {
 	count := 0
 	corpus := dawg.Corpus()
-	node := state.lastNode()
-	if node == nil {
+	if !state.Valid() {
 		return count
 	}
-	vertex := state.lastVertex()
-	if vertex != nil {
-		if vertex.final {
+	if state.WordLength() > 0 {
+		if state.Final() {
 			w := state.Word()
 			s := w.String(corpus)
 			_, found := content.FindWord(w)
@@ -174,9 +651,35 @@ func verifyMatchesInCorpusRecurse(t *testing.T, dawg Dawg, content CorpusContent
 			count++
 		}
 	}
-	for _, v := range node.vertices {
-		vs := state.Transition(v.letter)
-		count += verifyMatchesInCorpusRecurse(t, dawg, content, allWords, vs)
+	letters := state.Letters()
+	for l := Letter(0); int(l) < corpus.LetterMax(); l++ {
+		if letters.Test(l) {
+			vs := state.Transition(l)
+			count += verifyMatchesInCorpusRecurse(t, dawg, content, allWords, vs)
+		}
 	}
 	return count
 }
+
+func Benchmark_ValidContinuationsDK(b *testing.B) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		b.Fatalf("Benchmark_ValidContinuationsDK() failed to create corpus : %v", err)
+	}
+	content, err := corpus.GetFileContent("../data_test/dk_partial.txt")
+	if err != nil {
+		b.Fatalf("Benchmark_ValidContinuationsDK() failed to create corpus content : %v", err)
+	}
+	dawg, err := NewDawg(content, Options{})
+	if err != nil {
+		b.Fatalf("Benchmark_ValidContinuationsDK() failed to build dawg : %v", err)
+	}
+	words := content.Words()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, w := range words {
+			dawg.Transitions(w[:1]).ValidContinuations(w[2:])
+			dawg.Hooks(w)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package dawg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strings"
+	"unicode/utf8"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+)
+
+// Compiled dawg file layout (all integers little endian):
+//
+//	magic        [6]byte  "WFDAWG"
+//	version      uint16
+//	checksum     [32]byte checksum of the corpus content the dawg was built from
+//	alphabet     uint16 length + utf-8 letters in Letter order - a digraph letter in parentheses
+//	root         uint32 index of the first edge of the root node
+//	edgeCount    uint32
+//	edges        edgeCount * uint32 - the edges of the packed dawg
+//	crc          uint32 crc32 (IEEE) of everything above
+//
+// version 1 stored the nodes and vertices of the unpacked dawg
+
+const dawgFileMagic = "WFDAWG"
+const dawgFileVersion = uint16(2)
+const dawgFileExtension = ".dawg"
+
+func CompiledDawgFileName(corpusFileName string) string {
+	return CorpusFileBase(corpusFileName) + dawgFileExtension
+}
+
+// dawgAlphabet returns the letters of corpus - a letter of more than one character is in parentheses
+func dawgAlphabet(corpus Corpus) string {
+	var sb strings.Builder
+	for l := Letter(1); int(l) < corpus.LetterMax(); l++ {
+		if s := corpus.LetterToString(l); utf8.RuneCountInString(s) > 1 {
+			fmt.Fprintf(&sb, "(%s)", s)
+		} else {
+			sb.WriteString(s)
+		}
+	}
+	return sb.String()
+}
+
+func WriteDawgFile(fileName string, dawg Dawg, content CorpusContent) error {
+	var buf bytes.Buffer
+	if dawg.Corpus() != content.Corpus() {
+		return fmt.Errorf("dawg and corpus content do not share the same corpus")
+	}
+	packed, ok := dawg.(*_PackedDawg)
+	if !ok {
+		return fmt.Errorf("only a packed dawg can be written to a compiled dawg file")
+	}
+
+	checksum := content.Checksum()
+	alphabet := dawgAlphabet(dawg.Corpus())
+	buf.WriteString(dawgFileMagic)
+	binary.Write(&buf, binary.LittleEndian, dawgFileVersion)
+	buf.Write(checksum[:])
+	binary.Write(&buf, binary.LittleEndian, uint16(len(alphabet)))
+	buf.WriteString(alphabet)
+	binary.Write(&buf, binary.LittleEndian, packed.root)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(packed.edges)))
+	binary.Write(&buf, binary.LittleEndian, packed.edges)
+	binary.Write(&buf, binary.LittleEndian, crc32.ChecksumIEEE(buf.Bytes()))
+
+	tmpFileName := fileName + "~"
+	if err := os.WriteFile(tmpFileName, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFileName, fileName); err != nil {
+		os.Remove(tmpFileName)
+		return err
+	}
+	return nil
+}
+
+type dawgFileReader struct {
+	fileName string
+	data     []byte
+	pos      int
+}
+
+func (r *dawgFileReader) need(n int) error {
+	if r.pos+n > len(r.data) {
+		return fmt.Errorf("compiled dawg file \"%s\" is truncated", r.fileName)
+	}
+	return nil
+}
+
+func (r *dawgFileReader) bytes(n int) ([]byte, error) {
+	if err := r.need(n); err != nil {
+		return nil, err
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *dawgFileReader) uint16() (uint16, error) {
+	b, err := r.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (r *dawgFileReader) uint32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// ReadDawgFile loads a compiled dawg with a single read of fileName.
+// It fails if the file was compiled from a corpus content other than content.
+func ReadDawgFile(fileName string, content CorpusContent, options Options) (Dawg, error) {
+	Errorf := fmt.Errorf
+	data, err := ReadCorpusFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(dawgFileMagic)+4 {
+		return nil, Errorf("compiled dawg file \"%s\" is truncated", fileName)
+	}
+	payload := data[:len(data)-4]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(data[len(data)-4:]) {
+		return nil, Errorf("compiled dawg file \"%s\" is corrupt (crc mismatch)", fileName)
+	}
+	r := &dawgFileReader{fileName: fileName, data: payload}
+
+	magic, _ := r.bytes(len(dawgFileMagic))
+	if string(magic) != dawgFileMagic {
+		return nil, Errorf("\"%s\" is not a compiled dawg file", fileName)
+	}
+	version, err := r.uint16()
+	if err != nil {
+		return nil, err
+	}
+	if version != dawgFileVersion {
+		return nil, Errorf("compiled dawg file \"%s\" has version %d - expected version %d", fileName, version, dawgFileVersion)
+	}
+	checksum, err := r.bytes(len(Checksum{}))
+	if err != nil {
+		return nil, err
+	}
+	if Checksum(checksum) != content.Checksum() {
+		return nil, Errorf("compiled dawg file \"%s\" is stale - it was compiled from another corpus content", fileName)
+	}
+	alphabetLength, err := r.uint16()
+	if err != nil {
+		return nil, err
+	}
+	alphabet, err := r.bytes(int(alphabetLength))
+	if err != nil {
+		return nil, err
+	}
+	corpus := content.Corpus()
+	if string(alphabet) != dawgAlphabet(corpus) {
+		return nil, Errorf("compiled dawg file \"%s\" has alphabet \"%s\" which differs from the corpus alphabet", fileName, alphabet)
+	}
+	root, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	edgeCount, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	data, err = r.bytes(int(edgeCount) * 4)
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(r.data) {
+		return nil, Errorf("compiled dawg file \"%s\" has trailing data", fileName)
+	}
+	dawg := &_PackedDawg{
+		corpus:   corpus,
+		_options: options,
+		edges:    make([]edge, edgeCount),
+		root:     root,
+	}
+	dawg.nullState = packedState{dawg: dawg}
+	for i := range dawg.edges {
+		dawg.edges[i] = edge(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	if edgeCount == 0 || root >= edgeCount {
+		return nil, Errorf("compiled dawg file \"%s\" has invalid root edge %d", fileName, root)
+	}
+	for _, e := range dawg.edges[1:] {
+		if e.child() >= edgeCount {
+			return nil, Errorf("compiled dawg file \"%s\" has invalid edge index %d", fileName, e.child())
+		}
+	}
+	return dawg, nil
+}
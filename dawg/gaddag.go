@@ -0,0 +1,57 @@
+package dawg
+
+import (
+	"slices"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+)
+
+// GaddagSeparator separates the reversed prefix from the suffix of a word in a gaddag.
+// Every word w is stored as rev(w[:i]) + GaddagSeparator + w[i:] for 1 <= i < len(w) and as rev(w),
+// so a word can be grown in both directions from any of its letters.
+const GaddagSeparator = NoLetter
+
+// NewGaddag builds a gaddag from the words of content.
+// The gaddag is returned as a packed Dawg whose paths are the gaddag strings of the words.
+// An overlay file next to the corpus file is added to the gaddag.
+func NewGaddag(content CorpusContent, options Options) (Dawg, error) {
+	corpus := content.Corpus()
+	builder := newDawgBuilder(corpus, options)
+	words := content.Words()
+	// the gaddag strings must be added in sorted order - generate them one first letter at a time
+	// to avoid holding all of them at once
+	for letter := Letter(1); int(letter) < corpus.LetterMax(); letter++ {
+		bucket := make(Words, 0, 1000)
+		for _, w := range words {
+			for i := 1; i <= len(w); i++ {
+				if w[i-1] != letter {
+					continue
+				}
+				bucket = append(bucket, gaddagString(w, i))
+			}
+		}
+		slices.SortFunc(bucket, slices.Compare)
+		if err := builder.addWords(bucket); err != nil {
+			return nil, err
+		}
+	}
+	builder.finish()
+	gaddag, err := packDawg(builder.dawg)
+	if err != nil {
+		return nil, err
+	}
+	return withOverlay(gaddag, content, true)
+}
+
+func gaddagString(word Word, i int) Word {
+	n := len(word)
+	s := make(Word, 0, n+1)
+	for j := i - 1; j >= 0; j-- {
+		s = append(s, word[j])
+	}
+	if i < n {
+		s = append(s, GaddagSeparator)
+		s = append(s, word[i:]...)
+	}
+	return s
+}
@@ -0,0 +1,437 @@
+package dawg
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+)
+
+// An overlay holds words added to and removed from a dawg without rebuilding it.
+// It is persisted in a text file next to the corpus file with a line per word
+// "+WORD" for an added word and "-WORD" for a removed word.
+type Overlay struct {
+	Added   Words
+	Removed Words
+}
+
+// OverlayDawg is a dawg with the words of an overlay added and removed
+type OverlayDawg interface {
+	Dawg
+	Base() Dawg
+	Overlay() Overlay
+	Add(Word) error
+	Remove(Word) error
+}
+
+const overlayFileExtension = ".overlay"
+
+type _OverlayDawg struct {
+	sync.RWMutex
+	base     Dawg
+	expand   func(Word) Words // the paths of a word in base - the word itself in a dawg
+	added    map[string]Word
+	removed  map[string]Word
+	paths    map[string]bool      // the added paths
+	removals map[string]bool      // the removed paths
+	prefixes map[string]LetterSet // the letters following each prefix of the added paths
+}
+
+type overlayState struct {
+	dawg *_OverlayDawg
+	base DawgState
+	word Word
+}
+
+func OverlayFileName(corpusFileName string) string {
+	return CorpusFileBase(corpusFileName) + overlayFileExtension
+}
+
+func (overlay Overlay) Empty() bool {
+	return len(overlay.Added) == 0 && len(overlay.Removed) == 0
+}
+
+// ReadOverlayFile reads the overlay persisted in fileName - a missing file is an empty overlay
+func ReadOverlayFile(fileName string, corpus Corpus) (Overlay, error) {
+	var overlay Overlay
+	f, err := os.Open(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return overlay, nil
+	}
+	if err != nil {
+		return overlay, err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for lineNo := 1; s.Scan(); lineNo++ {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		word, err := corpus.ParseWord(line[1:])
+		if err == nil {
+			err = checkOverlayWord(word, corpus)
+		}
+		if err != nil {
+			return overlay, fmt.Errorf("overlay file \"%s\" line %d : invalid word \"%s\"", fileName, lineNo, line[1:])
+		}
+		switch line[0] {
+		case '+':
+			overlay.Added = append(overlay.Added, word)
+		case '-':
+			overlay.Removed = append(overlay.Removed, word)
+		default:
+			return overlay, fmt.Errorf("overlay file \"%s\" line %d : expected +WORD or -WORD", fileName, lineNo)
+		}
+	}
+	return overlay, s.Err()
+}
+
+// WriteOverlayFile persists overlay in fileName - an empty overlay removes the file
+func WriteOverlayFile(fileName string, overlay Overlay, corpus Corpus) error {
+	if overlay.Empty() {
+		err := os.Remove(fileName)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var sb strings.Builder
+	for _, w := range overlay.Added {
+		fmt.Fprintf(&sb, "+%s\n", w.String(corpus))
+	}
+	for _, w := range overlay.Removed {
+		fmt.Fprintf(&sb, "-%s\n", w.String(corpus))
+	}
+	return os.WriteFile(fileName, []byte(sb.String()), 0644)
+}
+
+// NewOverlayDawg returns base with the words of overlay added and removed
+func NewOverlayDawg(base Dawg, overlay Overlay) OverlayDawg {
+	return newOverlayDawg(base, overlay, func(word Word) Words { return Words{word} })
+}
+
+// newGaddagOverlay returns the gaddag base with the gaddag strings of the words of overlay added and removed
+func newGaddagOverlay(base Dawg, overlay Overlay) OverlayDawg {
+	return newOverlayDawg(base, overlay, func(word Word) Words {
+		paths := make(Words, len(word))
+		for i := range word {
+			paths[i] = gaddagString(word, i+1)
+		}
+		return paths
+	})
+}
+
+func newOverlayDawg(base Dawg, overlay Overlay, expand func(Word) Words) *_OverlayDawg {
+	dawg := &_OverlayDawg{
+		base:    base,
+		expand:  expand,
+		added:   make(map[string]Word),
+		removed: make(map[string]Word),
+	}
+	for _, w := range overlay.Added {
+		dawg.added[string(w)] = w
+	}
+	for _, w := range overlay.Removed {
+		dawg.removed[string(w)] = w
+	}
+	dawg.update()
+	return dawg
+}
+
+// update recalculates the paths of the added and removed words
+func (dawg *_OverlayDawg) update() {
+	dawg.paths = make(map[string]bool)
+	dawg.removals = make(map[string]bool)
+	dawg.prefixes = make(map[string]LetterSet)
+	for _, w := range dawg.added {
+		for _, p := range dawg.expand(w) {
+			dawg.paths[string(p)] = true
+			for i, l := range p {
+				letters := dawg.prefixes[string(p[:i])]
+				letters.Set(l)
+				dawg.prefixes[string(p[:i])] = letters
+			}
+		}
+	}
+	for _, w := range dawg.removed {
+		for _, p := range dawg.expand(w) {
+			dawg.removals[string(p)] = true
+		}
+	}
+}
+
+func (dawg *_OverlayDawg) inBase(word Word) bool {
+	return len(word) > 0 && dawg.base.Match(dawg.expand(word)[0])
+}
+
+// withOverlay adds the overlay persisted next to the corpus file of content (if any) to dawg
+func withOverlay(dawg Dawg, content CorpusContent, gaddag bool) (Dawg, error) {
+	if content.FileName() == "" {
+		return dawg, nil
+	}
+	overlay, err := ReadOverlayFile(OverlayFileName(content.FileName()), content.Corpus())
+	if err != nil || overlay.Empty() {
+		return dawg, err
+	}
+	if gaddag {
+		return newGaddagOverlay(dawg, overlay), nil
+	}
+	return NewOverlayDawg(dawg, overlay), nil
+}
+
+func (dawg *_OverlayDawg) Base() Dawg {
+	return dawg.base
+}
+
+func (dawg *_OverlayDawg) Overlay() Overlay {
+	dawg.RLock()
+	defer dawg.RUnlock()
+	overlay := Overlay{Added: make(Words, 0, len(dawg.added)), Removed: make(Words, 0, len(dawg.removed))}
+	for _, w := range dawg.added {
+		overlay.Added = append(overlay.Added, w)
+	}
+	for _, w := range dawg.removed {
+		overlay.Removed = append(overlay.Removed, w)
+	}
+	slices.SortFunc(overlay.Added, slices.Compare)
+	slices.SortFunc(overlay.Removed, slices.Compare)
+	return overlay
+}
+
+// checkOverlayWord returns an error unless word has at least the minimum word length of corpus
+// and only letters of its alphabet
+func checkOverlayWord(word Word, corpus Corpus) error {
+	if len(word) < corpus.MinWordLength() {
+		return fmt.Errorf("\"%s\" is not a word - a word has at least %d letters", word.String(corpus), corpus.MinWordLength())
+	}
+	for _, l := range word {
+		if l == NoLetter || int(l) >= corpus.LetterMax() {
+			return fmt.Errorf("\"%s\" has a letter which is not in the %s alphabet", word.String(corpus), corpus.Language().String())
+		}
+	}
+	return nil
+}
+
+// Add makes word a word of the dawg
+func (dawg *_OverlayDawg) Add(word Word) error {
+	if err := checkOverlayWord(word, dawg.Corpus()); err != nil {
+		return err
+	}
+	dawg.Lock()
+	defer dawg.Unlock()
+	delete(dawg.removed, string(word))
+	if !dawg.inBase(word) {
+		dawg.added[string(word)] = slices.Clone(word)
+	}
+	dawg.update()
+	return nil
+}
+
+// Remove makes word no longer a word of the dawg
+func (dawg *_OverlayDawg) Remove(word Word) error {
+	if err := checkOverlayWord(word, dawg.Corpus()); err != nil {
+		return err
+	}
+	dawg.Lock()
+	defer dawg.Unlock()
+	delete(dawg.added, string(word))
+	if dawg.inBase(word) {
+		dawg.removed[string(word)] = slices.Clone(word)
+	}
+	dawg.update()
+	return nil
+}
+
+func (dawg *_OverlayDawg) options() *Options {
+	return dawg.base.options()
+}
+
+func (dawg *_OverlayDawg) statistics() DawgStat {
+	return dawg.base.statistics()
+}
+
+func (dawg *_OverlayDawg) Corpus() Corpus {
+	return dawg.base.Corpus()
+}
+
+func (dawg *_OverlayDawg) InitialState() DawgState {
+	return overlayState{dawg: dawg, base: dawg.base.InitialState(), word: Word{}}
+}
+
+func (dawg *_OverlayDawg) Transition(letter Letter) DawgState {
+	return dawg.InitialState().Transition(letter)
+}
+
+func (dawg *_OverlayDawg) Transitions(word Word) DawgState {
+	return dawg.InitialState().Transitions(word)
+}
+
+func (dawg *_OverlayDawg) Match(word Word) bool {
+	return dawg.Transitions(word).Final()
+}
+
+func (dawg *_OverlayDawg) FindPrefix(word Word) DawgState {
+	state := dawg.InitialState()
+	for _, l := range word {
+		next := state.Transition(l)
+		if !next.Valid() {
+			break
+		}
+		state = next
+	}
+	return state
+}
+
+func (dawg *_OverlayDawg) Words() iter.Seq[Word] {
+	return stateWords(dawg.InitialState(), -1)
+}
+
+func (dawg *_OverlayDawg) WordsWithPrefix(prefix Word) iter.Seq[Word] {
+	return stateWords(dawg.Transitions(prefix), -1)
+}
+
+func (dawg *_OverlayDawg) WordsOfLength(length int) iter.Seq[Word] {
+	return stateWords(dawg.InitialState(), max(length, 0))
+}
+
+func (dawg *_OverlayDawg) Hooks(word Word) (LetterSet, LetterSet) {
+	return wordHooks(dawg, word)
+}
+
+// FoldOverlay builds a fresh minimized dawg holding the words of dawg
+func FoldOverlay(dawg OverlayDawg) (Dawg, Words, error) {
+	words := slices.Collect(dawg.Words())
+	builder := newDawgBuilder(dawg.Corpus(), *dawg.options())
+	if err := builder.addWords(words); err != nil {
+		return nil, nil, err
+	}
+	builder.finish()
+	folded, err := packDawg(builder.dawg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return folded, words, nil
+}
+
+func (state overlayState) Dawg() Dawg {
+	return state.dawg
+}
+
+func (state overlayState) Valid() bool {
+	if state.word == nil {
+		return false
+	}
+	if state.base.Valid() {
+		return true
+	}
+	state.dawg.RLock()
+	defer state.dawg.RUnlock()
+	_, found := state.dawg.prefixes[string(state.word)]
+	return found || state.dawg.paths[string(state.word)]
+}
+
+func (state overlayState) Final() bool {
+	if state.word == nil {
+		return false
+	}
+	key := string(state.word)
+	state.dawg.RLock()
+	defer state.dawg.RUnlock()
+	if state.dawg.paths[key] {
+		return true
+	}
+	return state.base.Final() && !state.dawg.removals[key]
+}
+
+func (state overlayState) Word() Word {
+	return slices.Clone(state.word)
+}
+
+func (state overlayState) WordLength() int {
+	return len(state.word)
+}
+
+func (state overlayState) Transition(letter Letter) DawgState {
+	if !state.Valid() {
+		return overlayState{dawg: state.dawg, base: state.base}
+	}
+	next := overlayState{
+		dawg: state.dawg,
+		base: state.base.Transition(letter),
+		word: append(slices.Clip(state.word), letter),
+	}
+	if !next.Valid() {
+		return overlayState{dawg: state.dawg, base: next.base}
+	}
+	return next
+}
+
+func (state overlayState) Transitions(word Word) DawgState {
+	var next DawgState = state
+	for _, l := range word {
+		next = next.Transition(l)
+	}
+	return next
+}
+
+func (state overlayState) Letters() LetterSet {
+	if !state.Valid() {
+		return NullLetterSet
+	}
+	state.dawg.RLock()
+	defer state.dawg.RUnlock()
+	return state.base.Letters() | state.dawg.prefixes[string(state.word)]
+}
+
+func (state overlayState) ValidContinuations(suffixes ...Word) LetterSet {
+	validContinuations := NullLetterSet
+	if !state.Valid() {
+		return validContinuations
+	}
+	if len(suffixes) == 0 {
+		suffixes = Words{Word{}}
+	}
+	letters := state.Letters()
+	for l := range letters.Letters() {
+		next := state.Transition(l)
+		for _, suffix := range suffixes {
+			if next.Transitions(suffix).Final() {
+				validContinuations.Set(l)
+				break
+			}
+		}
+	}
+	return validContinuations
+}
+
+func (state overlayState) Words() iter.Seq[Word] {
+	return stateWords(state, -1)
+}
+
+func (state overlayState) Print(args ...string) {
+	state.FprintState(os.Stdout, args...)
+}
+
+func (state overlayState) FprintState(f io.Writer, args ...string) {
+	indent := ""
+	if len(args) > 0 {
+		indent = args[0]
+	}
+	if !state.Valid() {
+		fmt.Fprintf(f, "%sstate <null>\n", indent)
+		return
+	}
+	corpus := state.dawg.Corpus()
+	fmt.Fprintf(f, "%soverlay state word:\"%s\"  final:%v  letters:%s\n", indent,
+		state.word.String(corpus), state.Final(), state.Letters().String(corpus))
+	state.base.FprintState(f, indent+"   ")
+}
@@ -0,0 +1,338 @@
+package dawg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+)
+
+// The packed dawg is an immutable array of edges (the vertices of the built dawg).
+// The edges leaving a node are stored consecutively and the last of them has edgeLast set.
+// A node is identified by the index of its first edge - index 0 is reserved and means
+// that the node has no edges.
+//
+//	bits  0..6   letter
+//	bit   7      final - the word ending with this edge is in the corpus
+//	bit   8      last edge of the node
+//	bits  9..31  index of the first edge of the destination node
+
+type edge uint32
+
+const (
+	edgeLetterBits = 7
+	edgeLetterMask = edge(1<<edgeLetterBits - 1)
+	edgeFinal      = edge(1 << 7)
+	edgeLast       = edge(1 << 8)
+	edgeChildShift = 9
+	edgeMaxChild   = uint32(1<<(32-edgeChildShift) - 1)
+)
+
+type _PackedDawg struct {
+	corpus    Corpus
+	_options  Options
+	edges     []edge
+	root      uint32
+	nullState DawgState // shared by the failed transitions
+}
+
+// packedWordMax is the number of letters of the word a packed state holds without allocating
+const packedWordMax = 32
+
+// packedState is a value - a transition copies the word of the state into the new state
+// instead of allocating it - only a word longer than packedWordMax is allocated
+type packedState struct {
+	dawg    *_PackedDawg
+	node    uint32
+	valid   bool
+	final   bool
+	length  int
+	letters [packedWordMax]Letter // the word when it has at most packedWordMax letters
+	long    Word                  // the word when it is longer
+}
+
+func (e edge) letter() Letter {
+	return Letter(e & edgeLetterMask)
+}
+
+func (e edge) final() bool {
+	return e&edgeFinal != 0
+}
+
+func (e edge) last() bool {
+	return e&edgeLast != 0
+}
+
+func (e edge) child() uint32 {
+	return uint32(e >> edgeChildShift)
+}
+
+func newEdge(letter Letter, final bool, last bool, child uint32) edge {
+	e := edge(letter) | edge(child)<<edgeChildShift
+	if final {
+		e |= edgeFinal
+	}
+	if last {
+		e |= edgeLast
+	}
+	return e
+}
+
+// packDawg converts the (minimized) pointer graph of a built dawg into the packed form
+func packDawg(dawg *_Dawg) (*_PackedDawg, error) {
+	packed := &_PackedDawg{
+		corpus:   dawg.corpus,
+		_options: dawg._options,
+		edges:    make([]edge, 1, 1000),
+	}
+	packed.nullState = packedState{dawg: packed}
+	nodeIndex := make(map[*node]uint32)
+	queue := nodes{dawg._rootNode}
+	// first pass: allocate the edges of every node
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if _, found := nodeIndex[n]; found || len(n.vertices) == 0 {
+			continue
+		}
+		if uint32(len(packed.edges)) > edgeMaxChild {
+			return nil, fmt.Errorf("dawg has more than %d edges which is the maximum of a packed dawg", edgeMaxChild)
+		}
+		nodeIndex[n] = uint32(len(packed.edges))
+		packed.edges = append(packed.edges, make([]edge, len(n.vertices))...)
+		for _, v := range n.vertices {
+			queue = append(queue, v.destination)
+		}
+	}
+	// second pass: fill in the edges
+	for n, index := range nodeIndex {
+		for i, v := range n.vertices {
+			if v.letter > Letter(edgeLetterMask) {
+				return nil, fmt.Errorf("letter %d cannot be represented in a packed dawg", v.letter)
+			}
+			packed.edges[index+uint32(i)] = newEdge(v.letter, v.final, i == len(n.vertices)-1, nodeIndex[v.destination])
+		}
+	}
+	packed.root = nodeIndex[dawg._rootNode]
+	return packed, nil
+}
+
+func (dawg *_PackedDawg) options() *Options {
+	return &dawg._options
+}
+
+func (dawg *_PackedDawg) Corpus() Corpus {
+	return dawg.corpus
+}
+
+func (dawg *_PackedDawg) InitialState() DawgState {
+	return packedState{dawg: dawg, node: dawg.root, valid: true}
+}
+
+// word returns the word of state - it is shared with state
+func (state *packedState) word() Word {
+	if state.long != nil {
+		return state.long
+	}
+	return state.letters[:state.length]
+}
+
+// appendWord adds word to the word of state
+func (state *packedState) appendWord(word ...Letter) {
+	length := state.length + len(word)
+	if length <= packedWordMax {
+		copy(state.letters[state.length:], word)
+	} else {
+		state.long = slices.Concat(state.word(), word)
+	}
+	state.length = length
+}
+
+// findEdge returns the edge for letter leaving node
+func (dawg *_PackedDawg) findEdge(node uint32, letter Letter) (edge, bool) {
+	if node == 0 {
+		return 0, false
+	}
+	for i := node; ; i++ {
+		e := dawg.edges[i]
+		if e.letter() == letter {
+			return e, true
+		}
+		if e.last() {
+			return 0, false
+		}
+	}
+}
+
+// walk follows word from node without collecting the word
+func (dawg *_PackedDawg) walk(node uint32, word Word) (uint32, bool, bool) {
+	final := false
+	for _, l := range word {
+		e, ok := dawg.findEdge(node, l)
+		if !ok {
+			return 0, false, false
+		}
+		node = e.child()
+		final = e.final()
+	}
+	return node, final, true
+}
+
+func (dawg *_PackedDawg) Transition(letter Letter) DawgState {
+	return dawg.InitialState().Transition(letter)
+}
+
+func (dawg *_PackedDawg) Transitions(word Word) DawgState {
+	return dawg.InitialState().Transitions(word)
+}
+
+func (dawg *_PackedDawg) Match(word Word) bool {
+	_, final, ok := dawg.walk(dawg.root, word)
+	return ok && final
+}
+
+func (dawg *_PackedDawg) FindPrefix(word Word) DawgState {
+	state := packedState{dawg: dawg, node: dawg.root, valid: true}
+	for i, l := range word {
+		e, ok := dawg.findEdge(state.node, l)
+		if !ok {
+			state.appendWord(word[:i]...)
+			return state
+		}
+		state.node = e.child()
+		state.final = e.final()
+	}
+	state.appendWord(word...)
+	return state
+}
+
+func (dawg *_PackedDawg) statistics() DawgStat {
+	var statistics DawgStat
+	statistics.VertexCount = len(dawg.edges) - 1
+	for _, e := range dawg.edges[1:] {
+		if e.last() {
+			statistics.NodeCount++
+		}
+	}
+	if statistics.VertexCount > 0 {
+		statistics.NodeCount++ // the final node without edges
+	} else {
+		statistics.NodeCount = 2 // an empty root node and the final node
+	}
+	return statistics
+}
+
+func (state packedState) Dawg() Dawg {
+	return state.dawg
+}
+
+func (state packedState) Valid() bool {
+	return state.valid
+}
+
+func (state packedState) Final() bool {
+	return state.valid && state.final
+}
+
+func (state packedState) Word() Word {
+	return slices.Clone(state.word())
+}
+
+func (state packedState) WordLength() int {
+	return state.length
+}
+
+func (state packedState) Transition(letter Letter) DawgState {
+	if !state.valid {
+		return state
+	}
+	e, ok := state.dawg.findEdge(state.node, letter)
+	if !ok {
+		return state.dawg.nullState
+	}
+	state.node = e.child()
+	state.final = e.final()
+	state.appendWord(letter)
+	return state
+}
+
+func (state packedState) Transitions(word Word) DawgState {
+	if !state.valid {
+		return state
+	}
+	node, final, ok := state.dawg.walk(state.node, word)
+	if !ok {
+		return state.dawg.nullState
+	}
+	if len(word) == 0 {
+		return state
+	}
+	state.node = node
+	state.final = final
+	state.appendWord(word...)
+	return state
+}
+
+func (state packedState) Letters() LetterSet {
+	letters := NullLetterSet
+	if !state.valid || state.node == 0 {
+		return letters
+	}
+	for i := state.node; ; i++ {
+		e := state.dawg.edges[i]
+		letters.Set(e.letter())
+		if e.last() {
+			return letters
+		}
+	}
+}
+
+func (state packedState) ValidContinuations(suffixes ...Word) LetterSet {
+	dawg := state.dawg
+	validContinuations := NullLetterSet
+	if !state.valid || state.node == 0 {
+		return validContinuations
+	}
+	if len(suffixes) == 0 {
+		suffixes = Words{Word{}}
+	}
+	for _, suffixWord := range suffixes {
+		if dawg._options.Debug > 0 && len(suffixWord) > 0 {
+			fmt.Printf("   suffix: %s\n", suffixWord.String(dawg.corpus))
+		}
+		for i := state.node; ; i++ {
+			e := dawg.edges[i]
+			if len(suffixWord) > 0 {
+				if _, final, ok := dawg.walk(e.child(), suffixWord); ok && final {
+					validContinuations.Set(e.letter())
+				}
+			} else if e.final() {
+				validContinuations.Set(e.letter())
+			}
+			if e.last() {
+				break
+			}
+		}
+	}
+	return validContinuations
+}
+
+func (state packedState) Print(args ...string) {
+	state.FprintState(os.Stdout, args...)
+}
+
+func (state packedState) FprintState(f io.Writer, args ...string) {
+	indent := ""
+	if len(args) > 0 {
+		indent = args[0]
+	}
+	if !state.valid {
+		fmt.Fprintf(f, "%sstate <null>\n", indent)
+		return
+	}
+	corpus := state.dawg.corpus
+	fmt.Fprintf(f, "%sstate node:%v  word:\"%s\"  final:%v  letters:%s\n", indent, state.node,
+		state.word().String(corpus), state.final, state.Letters().String(corpus))
+}
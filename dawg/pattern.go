@@ -0,0 +1,216 @@
+package dawg
+
+import (
+	"fmt"
+	"iter"
+	"unicode/utf8"
+	. "wordfeud/corpus"
+)
+
+// WordQuery selects words of a dawg.
+//
+// Pattern is matched against the whole word:
+//
+//	?        any single letter
+//	*        any number of letters (including none)
+//	[ABC]    one of the letters A, B or C
+//	[^ABC]   any letter but A, B and C
+//	A        the letter A - the longest letter is taken first so CH is a letter when it is a tile
+//
+// An empty pattern matches all words. Contains lists letters which must all be present in
+// the word - a letter repeated in Contains must be present as many times in the word.
+// MinLength and MaxLength limit the length of the words when they are above 0.
+type WordQuery struct {
+	Pattern   string
+	Contains  string
+	MinLength int
+	MaxLength int
+}
+
+type patternElement struct {
+	star    bool
+	letters LetterSet
+}
+
+// positions in the pattern - bit i is set when element i is the next to match
+// and bit len(elements) when the whole pattern has been matched
+type patternPositions uint64
+
+const patternMaxElements = 63
+
+type wordFinder struct {
+	corpus    Corpus
+	elements  []patternElement
+	contains  [AlphabetMax]int
+	needed    int
+	minLength int
+	maxLength int
+}
+
+func parsePattern(corpus Corpus, pattern string) ([]patternElement, error) {
+	if pattern == "" {
+		pattern = "*"
+	}
+	elements := make([]patternElement, 0, len(pattern))
+	parseLetter := func(i int) (Letter, int, error) {
+		letter, n := corpus.ParseLetter(pattern[i:])
+		if letter == NoLetter {
+			r, _ := utf8.DecodeRuneInString(pattern[i:])
+			return NoLetter, 0, fmt.Errorf("'%c' in pattern \"%s\" is not a letter of the %s alphabet", r, pattern, corpus.Language().String())
+		}
+		return letter, n, nil
+	}
+	for i := 0; i < len(pattern); {
+		switch pattern[i] {
+		case '*':
+			i++
+			if len(elements) > 0 && elements[len(elements)-1].star {
+				continue
+			}
+			elements = append(elements, patternElement{star: true, letters: corpus.AllLetters()})
+		case '?':
+			i++
+			elements = append(elements, patternElement{letters: corpus.AllLetters()})
+		case '[':
+			j := i + 1
+			negate := j < len(pattern) && pattern[j] == '^'
+			if negate {
+				j++
+			}
+			letters := NullLetterSet
+			for j < len(pattern) && pattern[j] != ']' {
+				letter, n, err := parseLetter(j)
+				if err != nil {
+					return nil, fmt.Errorf("invalid letter class : %s", err.Error())
+				}
+				letters.Set(letter)
+				j += n
+			}
+			if j >= len(pattern) {
+				return nil, fmt.Errorf("letter class in pattern \"%s\" is not terminated by ']'", pattern)
+			}
+			if negate {
+				letters = corpus.AllLetters() &^ letters
+			}
+			elements = append(elements, patternElement{letters: letters})
+			i = j + 1
+		default:
+			letter, n, err := parseLetter(i)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern : %s", err.Error())
+			}
+			elements = append(elements, patternElement{letters: LetterSet(1 << letter)})
+			i += n
+		}
+	}
+	if len(elements) > patternMaxElements {
+		return nil, fmt.Errorf("pattern \"%s\" has more than %d elements", pattern, patternMaxElements)
+	}
+	return elements, nil
+}
+
+// FindWords returns the words of dawg selected by query in corpus collation order.
+// The dawg is walked letter by letter following only the letters the pattern allows.
+func FindWords(dawg Dawg, query WordQuery) (iter.Seq[Word], error) {
+	corpus := dawg.Corpus()
+	elements, err := parsePattern(corpus, query.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	finder := &wordFinder{
+		corpus:    corpus,
+		elements:  elements,
+		minLength: query.MinLength,
+		maxLength: query.MaxLength,
+	}
+	contains, err := corpus.ParseWord(query.Contains)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range contains {
+		finder.contains[l]++
+	}
+	finder.needed = len(contains)
+	if !finder.hasStar() && (finder.maxLength <= 0 || len(elements) < finder.maxLength) {
+		finder.maxLength = len(elements)
+	}
+	return func(yield func(Word) bool) {
+		finder.walk(dawg.InitialState(), finder.closure(1), finder.contains, finder.needed, yield)
+	}, nil
+}
+
+func (finder *wordFinder) hasStar() bool {
+	for _, e := range finder.elements {
+		if e.star {
+			return true
+		}
+	}
+	return false
+}
+
+// closure adds the positions following stars (which may match no letters)
+func (finder *wordFinder) closure(positions patternPositions) patternPositions {
+	for i, e := range finder.elements {
+		if e.star && positions&(1<<i) != 0 {
+			positions |= 1 << (i + 1)
+		}
+	}
+	return positions
+}
+
+func (finder *wordFinder) matched(positions patternPositions) bool {
+	return positions&(1<<len(finder.elements)) != 0
+}
+
+func (finder *wordFinder) letters(positions patternPositions) LetterSet {
+	letters := NullLetterSet
+	for i, e := range finder.elements {
+		if positions&(1<<i) != 0 {
+			letters |= e.letters
+		}
+	}
+	return letters
+}
+
+func (finder *wordFinder) step(positions patternPositions, letter Letter) patternPositions {
+	next := patternPositions(0)
+	for i, e := range finder.elements {
+		if positions&(1<<i) == 0 || !e.letters.Test(letter) {
+			continue
+		}
+		if e.star {
+			next |= 1 << i
+		} else {
+			next |= 1 << (i + 1)
+		}
+	}
+	return finder.closure(next)
+}
+
+func (finder *wordFinder) walk(state DawgState, positions patternPositions, contains [AlphabetMax]int, needed int, yield func(Word) bool) bool {
+	length := state.WordLength()
+	if length > 0 && state.Final() && needed == 0 && finder.matched(positions) && length >= finder.minLength {
+		if !yield(state.Word()) {
+			return false
+		}
+	}
+	if finder.maxLength > 0 && length+max(needed, 1) > finder.maxLength {
+		return true
+	}
+	letters := state.Letters() & finder.letters(positions)
+	for l := range letters.Letters() {
+		next := finder.step(positions, l)
+		if next == 0 {
+			continue
+		}
+		nextContains, nextNeeded := contains, needed
+		if nextContains[l] > 0 {
+			nextContains[l]--
+			nextNeeded--
+		}
+		if !finder.walk(state.Transition(l), next, nextContains, nextNeeded, yield) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package dawg
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/text/language"
+)
+
+// dawgs are shared per language and dictionary version (the checksum of the corpus content)
+// and per version of the overlay file next to the corpus file - the dawgCacheSize most recently used are kept
+// a shared dawg keeps the options of the caller that caused it to be built - a failure is not shared
+
+const dawgCacheSize = 10
+
+type dawgKey struct {
+	language language.Tag
+	checksum Checksum
+	gaddag   bool
+	overlay  string // the name, size and modification time of the overlay file - empty when there is none
+}
+
+type dawgEntry struct {
+	once sync.Once
+	dawg Dawg
+	err  error
+}
+
+var dawgRegistry = struct {
+	sync.Mutex
+	dawgs *lru.Cache
+}{}
+
+func SharedDawg(content CorpusContent, options Options) (Dawg, error) {
+	return sharedDawg(content, options, false, NewDawg)
+}
+
+func SharedGaddag(content CorpusContent, options Options) (Dawg, error) {
+	return sharedDawg(content, options, true, NewGaddag)
+}
+
+func sharedDawg(content CorpusContent, options Options, gaddag bool, newDawg func(CorpusContent, Options) (Dawg, error)) (Dawg, error) {
+	key := dawgKey{language: content.Corpus().Language(), checksum: content.Checksum(), gaddag: gaddag, overlay: overlayVersion(content)}
+	entry := dawgEntryOf(key)
+	entry.once.Do(func() {
+		entry.dawg, entry.err = newDawg(content, options)
+	})
+	if entry.err != nil {
+		removeDawgEntry(key, entry)
+	}
+	if entry.err == nil && entry.dawg.Corpus() != content.Corpus() {
+		// same words but another corpus instance - do not share
+		return newDawg(content, options)
+	}
+	return entry.dawg, entry.err
+}
+
+func dawgEntryOf(key dawgKey) *dawgEntry {
+	dawgRegistry.Lock()
+	defer dawgRegistry.Unlock()
+	if dawgRegistry.dawgs == nil {
+		dawgRegistry.dawgs, _ = lru.New(dawgCacheSize)
+	}
+	if cached, found := dawgRegistry.dawgs.Get(key); found {
+		return cached.(*dawgEntry)
+	}
+	entry := &dawgEntry{}
+	dawgRegistry.dawgs.Add(key, entry)
+	return entry
+}
+
+// removeDawgEntry removes the failed entry of key unless it has been replaced
+func removeDawgEntry(key dawgKey, entry *dawgEntry) {
+	dawgRegistry.Lock()
+	defer dawgRegistry.Unlock()
+	if cached, found := dawgRegistry.dawgs.Peek(key); found && cached.(*dawgEntry) == entry {
+		dawgRegistry.dawgs.Remove(key)
+	}
+}
+
+// overlayVersion returns the version of the overlay file of content - empty when there is none
+func overlayVersion(content CorpusContent) string {
+	if content.FileName() == "" {
+		return ""
+	}
+	fileName := OverlayFileName(content.FileName())
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d-%d", fileName, info.Size(), info.ModTime().UnixNano())
+}
@@ -0,0 +1,94 @@
+package dawg
+
+import (
+	"iter"
+	. "wordfeud/corpus"
+)
+
+// stateWords iterates the words reachable from state (including the word of state itself)
+// in corpus collation order. If length is not negative only words of that length are returned.
+func stateWords(state DawgState, length int) iter.Seq[Word] {
+	return func(yield func(Word) bool) {
+		if !state.Valid() {
+			return
+		}
+		if state.WordLength() > 0 && state.Final() && (length < 0 || state.WordLength() == length) {
+			if !yield(state.Word()) {
+				return
+			}
+		}
+		walkWords(state, length, yield)
+	}
+}
+
+func walkWords(state DawgState, length int, yield func(Word) bool) bool {
+	if length >= 0 && state.WordLength() >= length {
+		return true
+	}
+	letters := state.Letters()
+	for l := range letters.Letters() {
+		next := state.Transition(l)
+		if next.Final() && (length < 0 || next.WordLength() == length) {
+			if !yield(next.Word()) {
+				return false
+			}
+		}
+		if !walkWords(next, length, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Words iterates all words of the dawg in corpus collation order
+func (dawg *_Dawg) Words() iter.Seq[Word] {
+	return stateWords(dawg._initialState, -1)
+}
+
+// WordsWithPrefix iterates the words starting with prefix (including prefix itself)
+func (dawg *_Dawg) WordsWithPrefix(prefix Word) iter.Seq[Word] {
+	return stateWords(dawg.Transitions(prefix), -1)
+}
+
+// WordsOfLength iterates the words with length letters
+func (dawg *_Dawg) WordsOfLength(length int) iter.Seq[Word] {
+	return stateWords(dawg._initialState, max(length, 0))
+}
+
+func (state *_DawgState) Words() iter.Seq[Word] {
+	return stateWords(state, -1)
+}
+
+func (dawg *_PackedDawg) Words() iter.Seq[Word] {
+	return stateWords(dawg.InitialState(), -1)
+}
+
+func (dawg *_PackedDawg) WordsWithPrefix(prefix Word) iter.Seq[Word] {
+	return stateWords(dawg.Transitions(prefix), -1)
+}
+
+func (dawg *_PackedDawg) WordsOfLength(length int) iter.Seq[Word] {
+	return stateWords(dawg.InitialState(), max(length, 0))
+}
+
+func (state packedState) Words() iter.Seq[Word] {
+	return stateWords(state, -1)
+}
+
+// wordHooks returns the letters which put in front of word (front hooks) or after word (back hooks) form a word
+func wordHooks(dawg Dawg, word Word) (LetterSet, LetterSet) {
+	if len(word) == 0 {
+		return NullLetterSet, NullLetterSet
+	}
+	front := dawg.InitialState().ValidContinuations(word)
+	back := dawg.Transitions(word).ValidContinuations()
+	return front, back
+}
+
+func (dawg *_Dawg) Hooks(word Word) (LetterSet, LetterSet) {
+	return wordHooks(dawg, word)
+}
+
+func (dawg *_PackedDawg) Hooks(word Word) (LetterSet, LetterSet) {
+	return wordHooks(dawg, word)
+}
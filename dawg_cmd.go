@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"strings"
 	. "wordfeud/context"
 	. "wordfeud/corpus"
 	. "wordfeud/dawg"
@@ -13,8 +15,18 @@ import (
 func dawgCmd(options *GameOptions, args []string) *DawgResult {
 	result := new(DawgResult)
 
+	var compile bool
+	var list bool
+	var fold bool
+	var prefixSpec string
+	var length int
 	flag := flag.NewFlagSet("exit", flag.ExitOnError)
 	registerGlobalFlags(flag)
+	BoolVarFlag(flag, &compile, []string{"compile"}, false, "build the dawg and write it as a compiled dawg file next to the corpus file")
+	BoolVarFlag(flag, &fold, []string{"fold"}, false, "fold the overlay into the corpus file and write a fresh compiled dawg file")
+	BoolVarFlag(flag, &list, []string{"list"}, false, "list the words of the dawg in corpus collation order")
+	StringVarFlag(flag, &prefixSpec, []string{"prefix"}, "", "only list words starting with prefix")
+	IntVarFlag(flag, &length, []string{"length"}, 0, "only list words of length letters")
 
 	flag.Parse(args)
 	var corpus Corpus
@@ -33,11 +45,38 @@ func dawgCmd(options *GameOptions, args []string) *DawgResult {
 		fmt.Println(result.errors(), err.Error())
 		return result.result()
 	}
-	dawg, err = NewDawg(content, options.Options)
+	if compile {
+		dawg, err = BuildDawg(content, options.Options)
+	} else {
+		dawg, err = NewDawg(content, options.Options)
+	}
 	if err != nil {
 		fmt.Println(result.errors(), err.Error())
 		return result.result()
 	}
+	if fold {
+		overlayDawg, ok := dawg.(OverlayDawg)
+		if !ok {
+			fmt.Fprintf(result.errors(), "there is no overlay file \"%s\" to fold\n", OverlayFileName(fileName))
+			return result.result()
+		}
+		dawg, content, err = foldOverlay(overlayDawg, fileName)
+		if err != nil {
+			fmt.Println(result.errors(), err.Error())
+			return result.result()
+		}
+		result.CompiledFile = CompiledDawgFileName(fileName)
+		p := message.NewPrinter(options.Language)
+		overlay := overlayDawg.Overlay()
+		p.Fprintf(result.logger(), "Folded %d added and %d removed words into \"%s\"\n", len(overlay.Added), len(overlay.Removed), fileName)
+	}
+	if compile {
+		result.CompiledFile = CompiledDawgFileName(fileName)
+		if err = WriteDawgFile(result.CompiledFile, dawg, content); err != nil {
+			fmt.Println(result.errors(), err.Error())
+			return result.result()
+		}
+	}
 	statistics := DawgStatistics(dawg)
 	result.NodeCount = statistics.NodeCount
 	result.VertexCount = statistics.VertexCount
@@ -50,6 +89,63 @@ func dawgCmd(options *GameOptions, args []string) *DawgResult {
 	p.Fprintf(result.logger(), "Node count            : %d\n", result.NodeCount)
 	p.Fprintf(result.logger(), "Vertex count          : %d\n", result.VertexCount)
 	p.Fprintf(result.logger(), "Node and Vertex count : %d   %d%%\n", result.NodeCount+result.VertexCount, ((result.NodeCount+result.VertexCount)*100)/corpusStat.TotalWordsSize)
+	p.Fprintf(result.logger(), "Corpus checksum       : %s\n", content.Checksum().String())
+	if compile {
+		p.Fprintf(result.logger(), "Compiled dawg file    : %s\n", result.CompiledFile)
+	}
+	if list {
+		prefix, err := corpus.ParseWord(prefixSpec)
+		if err != nil {
+			fmt.Println(result.errors(), err.Error())
+			return result.result()
+		}
+		words := dawg.WordsWithPrefix(prefix)
+		if len(prefix) == 0 && length > 0 {
+			words = dawg.WordsOfLength(length)
+		}
+		result.Words = make([]string, 0)
+		for w := range words {
+			if length > 0 && len(w) != length {
+				continue
+			}
+			s := w.String(corpus)
+			result.Words = append(result.Words, s)
+			fmt.Fprintln(result.logger(), s)
+		}
+	}
 
 	return result.result()
 }
+
+// foldOverlay replaces the words of corpus file fileName with the words of dawg, writes the
+// compiled dawg file of the folded dawg and removes the overlay file
+func foldOverlay(dawg OverlayDawg, fileName string) (Dawg, CorpusContent, error) {
+	corpus := dawg.Corpus()
+	folded, words, err := FoldOverlay(dawg)
+	if err != nil {
+		return nil, nil, err
+	}
+	var sb strings.Builder
+	for _, w := range words {
+		sb.WriteString(w.String(corpus))
+		sb.WriteByte('\n')
+	}
+	tmpFileName := fileName + "~"
+	if err = os.WriteFile(tmpFileName, []byte(sb.String()), 0644); err != nil {
+		return nil, nil, err
+	}
+	if err = os.Rename(tmpFileName, fileName); err != nil {
+		return nil, nil, err
+	}
+	content, err := corpus.GetFileContent(fileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = WriteDawgFile(CompiledDawgFileName(fileName), folded, content); err != nil {
+		return nil, nil, err
+	}
+	if err = WriteOverlayFile(OverlayFileName(fileName), Overlay{}, corpus); err != nil {
+		return nil, nil, err
+	}
+	return folded, content, nil
+}
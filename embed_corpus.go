@@ -0,0 +1,41 @@
+//go:build embedcorpus
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+	. "wordfeud/corpus"
+	. "wordfeud/template"
+)
+
+// Built with
+//
+//	go generate -tags embedcorpus
+//	go build -tags embedcorpus
+//
+// the binary holds the gzip compressed corpus files and the compiled dawgs of data and the
+// templates so autoplay and serve run in any directory. The files must be present at build
+// time - go generate writes them from the corpus files in data (embed_generate.go) and fails
+// with an explanation when there are none. Corpus files on disk take precedence over the embedded ones.
+
+//go:generate go run embed_generate.go
+
+//go:embed data/corpus_*.gz data/corpus_*.dawg
+var embeddedData embed.FS
+
+//go:embed templates/*.html templates/*.css templates/*.js
+var embeddedTemplates embed.FS
+
+func init() {
+	data, err := fs.Sub(embeddedData, "data")
+	if err != nil {
+		panic(err)
+	}
+	EmbedCorpusFiles(data)
+	templates, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		panic(err)
+	}
+	EmbedTemplates(templates)
+}
@@ -0,0 +1,81 @@
+//go:build ignore
+
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+	. "wordfeud/dawg"
+
+	"golang.org/x/text/language"
+)
+
+// Run by "go generate -tags embedcorpus" before "go build -tags embedcorpus" - it writes the
+// gzip compressed corpus file and the compiled dawg of each language with a corpus file in data
+// (e.g. data/corpus_dk.txt.gz and data/corpus_dk.dawg from data/corpus_dk.txt) for the binary to embed
+
+func main() {
+	generated := 0
+	for _, lang := range SupportedLanguages() {
+		fileName := GetLanguageFileName(lang)
+		if _, err := os.Stat(fileName); err != nil {
+			continue
+		}
+		if err := gzipFile(fileName); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if err := compileDawg(lang, fileName); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("%s : %s.gz and %s\n", lang.String(), fileName, CompiledDawgFileName(fileName))
+		generated++
+	}
+	if generated == 0 {
+		fmt.Fprintln(os.Stderr, "there are no corpus files in data to embed - a build with -tags embedcorpus embeds data/corpus_*.gz")
+		fmt.Fprintln(os.Stderr, "and data/corpus_*.dawg - put the corpus files (e.g. data/corpus_dk.txt) in data and run go generate again")
+		os.Exit(1)
+	}
+}
+
+func gzipFile(fileName string) error {
+	in, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(fileName + ".gz")
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w := gzip.NewWriter(out)
+	if _, err = io.Copy(w, in); err != nil {
+		return fmt.Errorf("cannot compress \"%s\" : %v", fileName, err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("cannot compress \"%s\" : %v", fileName, err)
+	}
+	return out.Close()
+}
+
+func compileDawg(lang language.Tag, fileName string) error {
+	corpus, err := NewCorpus(lang)
+	if err != nil {
+		return err
+	}
+	content, err := corpus.GetFileContent(fileName)
+	if err != nil {
+		return err
+	}
+	dawg, err := BuildDawg(content, Options{})
+	if err != nil {
+		return err
+	}
+	return WriteDawgFile(CompiledDawgFileName(fileName), dawg, content)
+}
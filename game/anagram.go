@@ -0,0 +1,96 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+	. "wordfeud/corpus"
+	. "wordfeud/dawg"
+)
+
+// JokerRune is the character of a joker (blank) tile when a rack is written as a string
+const JokerRune = '?'
+
+type Anagram struct {
+	Word  Word
+	Tiles Tiles // the rack tiles spelling Word - a joker has the letter it is used as
+	Score Score // the sum of the letter values - a joker scores 0
+}
+
+type Anagrams []Anagram
+
+type AnagramGroup struct {
+	Length   int
+	Full     bool // the words use all tiles of the rack
+	Anagrams Anagrams
+}
+
+type AnagramGroups []AnagramGroup
+
+// ParseRack converts a string of letters and jokers ('?') to a rack
+func ParseRack(corpus Corpus, rackSpec string) (Rack, error) {
+	rack := make(Rack, 0, RackSize)
+	for i := 0; i < len(rackSpec); {
+		r, size := utf8.DecodeRuneInString(rackSpec[i:])
+		if r == JokerRune {
+			rack = append(rack, Tile{kind: TILE_JOKER, letter: 0})
+			i += size
+			continue
+		}
+		letter, n := corpus.ParseLetter(rackSpec[i:])
+		if letter == NoLetter {
+			return nil, fmt.Errorf("'%c' in rack \"%s\" is neither a letter of the %s alphabet nor a joker (%c)", r, rackSpec, corpus.Language().String(), JokerRune)
+		}
+		rack = append(rack, Tile{kind: TILE_LETTER, letter: letter})
+		i += n
+	}
+	return rack, nil
+}
+
+// FindAnagrams returns the words of at least minLength letters which can be made from the tiles of rack.
+// The words are grouped by length - longest first - and each group is ordered by score (best first).
+// A word which can be made in several ways (using a joker or not) is returned with the best score.
+func FindAnagrams(dawg Dawg, rack Rack, minLength int) AnagramGroups {
+	corpus := dawg.Corpus()
+	letterScores := NewLetterScores(corpus)
+	best := make(map[string]Anagram)
+	var find func(state DawgState, rack Rack, tiles Tiles, score Score)
+	find = func(state DawgState, rack Rack, tiles Tiles, score Score) {
+		if state.Final() && len(tiles) >= minLength {
+			key := state.Word().String(corpus)
+			if a, found := best[key]; !found || score > a.Score {
+				best[key] = Anagram{Word: state.Word(), Tiles: tiles, Score: score}
+			}
+		}
+		letters := state.Letters()
+		for _, rackTile := range rack.RackTiles(corpus) {
+			if !letters.Test(rackTile.tile.letter) {
+				continue
+			}
+			tileScore := Score(0)
+			if rackTile.tile.kind == TILE_LETTER {
+				tileScore = letterScores[rackTile.tile.letter]
+			}
+			find(state.Transition(rackTile.tile.letter), rackTile.rack, append(slices.Clip(tiles), rackTile.tile), score+tileScore)
+		}
+	}
+	find(dawg.InitialState(), rack, Tiles{}, 0)
+
+	groups := make(AnagramGroups, 0)
+	for _, a := range best {
+		i := slices.IndexFunc(groups, func(g AnagramGroup) bool { return g.Length == len(a.Word) })
+		if i < 0 {
+			groups = append(groups, AnagramGroup{Length: len(a.Word), Full: len(a.Word) == len(rack)})
+			i = len(groups) - 1
+		}
+		groups[i].Anagrams = append(groups[i].Anagrams, a)
+	}
+	slices.SortFunc(groups, func(l AnagramGroup, r AnagramGroup) int { return r.Length - l.Length })
+	for _, g := range groups {
+		slices.SortFunc(g.Anagrams, func(l Anagram, r Anagram) int {
+			return cmp.Or(cmp.Compare(r.Score, l.Score), slices.Compare(l.Word, r.Word))
+		})
+	}
+	return groups
+}
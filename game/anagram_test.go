@@ -0,0 +1,75 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+	. "wordfeud/dawg"
+
+	"golang.org/x/text/language"
+)
+
+func Test_FindAnagrams(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_FindAnagrams() failed to create corpus : %v", err)
+		return
+	}
+	content, err := corpus.NewContent(strings.NewReader("ABE\nBAD\nBADE\nBEDE\nDA\nED\nKAT\nØDE"))
+	if err != nil {
+		t.Errorf("Test_FindAnagrams() failed to create corpus content : %v", err)
+		return
+	}
+	dawg, err := BuildDawg(content, Options{})
+	if err != nil {
+		t.Errorf("Test_FindAnagrams() failed to create dawg : %v", err)
+		return
+	}
+	rack, err := ParseRack(corpus, "dab?")
+	if err != nil {
+		t.Errorf("Test_FindAnagrams() failed to parse rack : %v", err)
+		return
+	}
+	letterScores := NewLetterScores(corpus)
+	score := func(s string) Score {
+		total := Score(0)
+		for _, r := range s {
+			total += letterScores[corpus.StringToLetter(string(r))]
+		}
+		return total
+	}
+	// the rack has a single joker so BEDE (two E) and ØDE (Ø and E) cannot be made
+	expected := []struct {
+		length int
+		full   bool
+		words  string
+		scores string
+	}{
+		{4, true, "BADE", "BAD"},
+		{3, false, "BAD ABE", "BAD AB"},
+		{2, false, "DA ED", "DA D"},
+	}
+	groups := FindAnagrams(dawg, rack, 2)
+	if len(groups) != len(expected) {
+		t.Errorf("FindAnagrams returned %d groups - expected %d", len(groups), len(expected))
+		return
+	}
+	for i, group := range groups {
+		words := make([]string, len(group.Anagrams))
+		scores := make([]Score, len(group.Anagrams))
+		for j, a := range group.Anagrams {
+			words[j] = a.Word.String(corpus)
+			scores[j] = a.Score
+		}
+		expectedScores := make([]Score, 0)
+		for _, s := range strings.Fields(expected[i].scores) {
+			expectedScores = append(expectedScores, score(s))
+		}
+		if group.Length != expected[i].length || group.Full != expected[i].full ||
+			strings.Join(words, " ") != expected[i].words || !slices.Equal(scores, expectedScores) {
+			t.Errorf("FindAnagrams group %d is %d %v %v %v - expected %+v", i, group.Length, group.Full, words, scores, expected[i])
+		}
+	}
+}
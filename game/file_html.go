@@ -4,11 +4,14 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"io/fs"
 	"os"
 	"path"
 	"regexp"
+	"strings"
+	. "wordfeud/corpus"
 	. "wordfeud/localize"
 
 	"golang.org/x/text/language"
@@ -96,7 +99,7 @@ func updateGameHtml(game Game, dirName string, gameEnded bool, messages Messages
 		return nil
 	}
 	if _game.nextWriteSeqNo == 0 {
-		if err = writeHtmlStyles(dirName); err != nil {
+		if err = WriteHtmlStyles(dirName); err != nil {
 			return err
 		}
 		if err = writeHtmlScript(dirName); err != nil {
@@ -130,7 +133,7 @@ func updateIndexHtml(game *_Game, states GameStates, dirName string, messages Me
 		}
 	}()
 
-	writeHtmlHeader(f, p, lang)
+	writeHtmlHeader(f, game, p, lang)
 	p.Fprintln(f, "<body>")
 	// HEADER
 	if _, err = p.Fprintln(f, `<div class="canvas">`); err != nil {
@@ -141,6 +144,16 @@ func updateIndexHtml(game *_Game, states GameStates, dirName string, messages Me
 		Localized(lang, "Scrabble game"), game.options.Name, game.seqno); err != nil {
 		return err
 	}
+	header := game.Header()
+	dictionary := header.Dictionary
+	if dictionary == "" {
+		dictionary = path.Base(game.content.FileName())
+	}
+	if _, err = p.Fprintf(f, `<div class="dictionary">%s %s %s - %s %s</div>`+"\n",
+		Localized(lang, "Dictionary"), html.EscapeString(dictionary), header.Fingerprint[:16],
+		Localized(lang, "ruleset"), header.Ruleset); err != nil {
+		return err
+	}
 	p.Fprintln(f, `</div>`)
 
 	if _, err = p.Fprintln(f, `<div class="canvas">`); err != nil {
@@ -277,7 +290,7 @@ func updateMoveHtml(state *GameState, lastMove uint, dirName string, gameEnded b
 		}
 	}()
 
-	writeHtmlHeader(f, p, lang)
+	writeHtmlHeader(f, state.game, p, lang)
 	p.Fprintln(f, "<body>")
 	// HEADER
 	p.Fprintln(f, `<div class="canvas">`)
@@ -290,6 +303,9 @@ func updateMoveHtml(state *GameState, lastMove uint, dirName string, gameEnded b
 		p.Fprintf(f, `<div class="header">`+Localized(lang, "Move number %d")+`</div>`, move.seqno)
 		p.Fprintf(f, `<div class="move">`+Localized(lang, `%s played "%s" %s at %s giving %d points`)+`</div>`,
 			player.name, word, move.direction.Orientation().Localized(lang), startPos.String(), move.score.score)
+		if info := wordInfo(state.game, state.game.TilesToWord(move.tiles.Tiles()), p, lang); info != "" {
+			p.Fprintf(f, `<div class="word-info">%s</div>`, html.EscapeString(info))
+		}
 	}
 	p.Fprintln(f, `</div>`)
 
@@ -339,16 +355,45 @@ func updateMoveHtml(state *GameState, lastMove uint, dirName string, gameEnded b
 	return nil
 }
 
-func writeHtmlHeader(f io.Writer, p *message.Printer, lang language.Tag) {
+// wordInfo returns the metadata of word as text - empty when there is none
+func wordInfo(game *_Game, word Word, p *message.Printer, lang language.Tag) string {
+	metadata, found := game.content.Metadata(word)
+	if !found {
+		return ""
+	}
+	w := word.String(game.corpus)
+	parts := make([]string, 0, 3)
+	if metadata.Lemma != "" && !strings.EqualFold(metadata.Lemma, w) {
+		parts = append(parts, p.Sprintf(Localized(lang, `"%s" is a form of "%s"`), w, metadata.Lemma))
+	} else {
+		parts = append(parts, p.Sprintf(`"%s"`, w))
+	}
+	if metadata.POS != "" {
+		parts[0] += " (" + metadata.POS + ")"
+	}
+	if metadata.FrequencyRank > 0 {
+		parts = append(parts, p.Sprintf(Localized(lang, "frequency rank %d"), metadata.FrequencyRank))
+	}
+	if metadata.Gloss != "" {
+		parts = append(parts, metadata.Gloss)
+	}
+	return strings.Join(parts, " - ")
+}
+
+func writeHtmlHeader(f io.Writer, game *_Game, p *message.Printer, lang language.Tag) {
 	p.Fprintf(f, `<!DOCTYPE html>
 <html lang="%s">
 <head>
     <title>HTML Other Lists</title>
     <meta charset="utf-8">
-	<link rel="stylesheet" href="styles.css">
+`, lang.String())
+	for _, entry := range game.Header().entries() {
+		p.Fprintf(f, `    <meta name="wordfeud-%s" content="%s">`+"\n", entry[0], html.EscapeString(entry[1]))
+	}
+	p.Fprintf(f, `	<link rel="stylesheet" href="styles.css">
 	<script src="script.js"></script>
 </head>
-	`, lang.String())
+	`)
 }
 
 func writeHtmlPlayerStates(f io.Writer, state *GameState) error {
@@ -566,7 +611,8 @@ func FprintHtmlStateBoard(f io.Writer, state *GameState) error {
 //go:embed styles.css
 var cssStyles string
 
-func writeHtmlStyles(dirName string) error {
+// WriteHtmlStyles writes the styles of the html game files (the board, the racks and the players) to dirName
+func WriteHtmlStyles(dirName string) error {
 	var err error
 	var f *os.File
 
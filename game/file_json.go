@@ -1,7 +1,86 @@
 package game
 
-import "io"
+import (
+	"encoding/json"
+	"io"
+)
+
+type gameFileJson struct {
+	Header   *GameHeader          `json:"header"`
+	Messages []string             `json:"messages"`
+	Players  []gameFilePlayerJson `json:"players"`
+	Moves    []gameFileMoveJson   `json:"moves"`
+}
+
+type gameFilePlayerJson struct {
+	Name  string `json:"name"`
+	Score Score  `json:"score"`
+	Rack  string `json:"rack"`
+}
+
+type gameFileMoveJson struct {
+	SeqNo     uint   `json:"seqno"`
+	Player    string `json:"player"`
+	Kind      string `json:"kind"` // move, pass, exchange or resign
+	Position  string `json:"position"`
+	Direction string `json:"direction"`
+	Word      string `json:"word"` // empty unless kind is move
+	Exchanged int    `json:"exchanged,omitempty"`
+	Score     Score  `json:"score"`
+}
 
 func WriteGameFileJson(f io.Writer, game Game, messages Messages) error {
-	panic("WriteGameFileJson unimplemented")
+	_game := game._Game()
+	corpus := _game.corpus
+	file := gameFileJson{
+		Header:   _game.Header(),
+		Messages: make([]string, 0),
+		Players:  make([]gameFilePlayerJson, 0, len(_game.players)),
+		Moves:    make([]gameFileMoveJson, 0),
+	}
+	for _, category := range AllMessageCategories {
+		file.Messages = append(file.Messages, messages[category]...)
+	}
+	for _, ps := range _game.state.playerStates {
+		if ps.player.id == SystemPlayerId {
+			continue
+		}
+		file.Players = append(file.Players, gameFilePlayerJson{Name: ps.player.name, Score: ps.score, Rack: ps.rack.String(corpus)})
+	}
+	for _, state := range _game.CollectStates() {
+		move := state.move
+		if move == nil {
+			continue
+		}
+		file.Moves = append(file.Moves, gameFileMoveJson{
+			SeqNo:     move.seqno,
+			Player:    move.playerState.player.name,
+			Kind:      move.kind.String(),
+			Position:  move.position.String(),
+			Direction: move.direction.String(),
+			Word:      state.TilesToString(move.tiles.Tiles()),
+			Exchanged: move.exchanged,
+			Score:     move.score.score,
+		})
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// ReadGameFileJson reads the header and the words of the moves placing tiles of the json game file of r
+// - the header is nil when r is not a game file
+func ReadGameFileJson(r io.Reader) (*GameHeader, []string, error) {
+	var file gameFileJson
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, nil, err
+	}
+	words := make([]string, 0, len(file.Moves))
+	for _, move := range file.Moves {
+		// the moves of files written before the kind was recorded have a word only when tiles were placed
+		if move.Word != "" && (move.Kind == "" || move.Kind == MOVE_PLACE.String()) {
+			words = append(words, move.Word)
+		}
+	}
+	return file.Header, words, nil
 }
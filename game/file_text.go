@@ -14,6 +14,11 @@ func WriteGameFileText(f io.Writer, game Game, messages Messages) error {
 	if _, err = fmt.Fprintf(f, Localized(lang, "Scrabble game")+" %s-%d\n\n", _game.options.Name, _game.seqno); err != nil {
 		return err
 	}
+	for _, entry := range _game.Header().entries() {
+		if _, err = fmt.Fprintf(f, "%-12s %s\n", entry[0]+":", entry[1]); err != nil {
+			return err
+		}
+	}
 
 	fmt.Fprintln(f, "")
 
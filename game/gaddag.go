@@ -0,0 +1,202 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	. "wordfeud/dawg"
+)
+
+// gaddagMove is a move under construction by the gaddag move generator.
+// left holds the tiles from the anchor towards the prefix direction (in gaddag order)
+// and right the tiles following the anchor
+type gaddagMove struct {
+	anchor      Position
+	orientation Orientation
+	rack        Rack
+	state       DawgState
+	leftPos     Position
+	left        MoveTiles
+	right       MoveTiles
+}
+
+// GenerateAllGaddagMoves generates the same moves as GenerateAllDawgMoves but grows the words
+// in both directions from each anchor using the gaddag of the game
+func (state *GameState) GenerateAllGaddagMoves(playerState *PlayerState) PartialMoves {
+	options := state.game.options
+	corpus := state.game.corpus
+	if options.Debug > 0 {
+		fmt.Printf("\n\n--------------------------------\n GenerateAllGaddagMoves: player: %s\n", playerState.String(corpus))
+		PrintState(state)
+	}
+	playerState.rack.Verify(corpus)
+	out := make(PartialMoves, 0, 100)
+	width := state.game.Dimensions().Width
+	height := state.game.Dimensions().Height
+	for r := Coordinate(0); r < height; r++ {
+		for _, anchor := range state.GetAnchors(r, HORIZONTAL) {
+			out = slices.Concat(out, state.GenerateAllGaddagMovesForAnchor(playerState, anchor, HORIZONTAL))
+		}
+	}
+	for c := Coordinate(0); c < width; c++ {
+		for _, anchor := range state.GetAnchors(c, VERTICAL) {
+			out = slices.Concat(out, state.GenerateAllGaddagMovesForAnchor(playerState, anchor, VERTICAL))
+		}
+	}
+	return out
+}
+
+func (state *GameState) GenerateAllGaddagMovesForAnchor(playerState *PlayerState, anchor Position, orientation Orientation) PartialMoves {
+	game := state.game
+	if game.gaddag == nil {
+		panic("game has no gaddag (GameState.GenerateAllGaddagMovesForAnchor)")
+	}
+	out := make(PartialMoves, 0, 10)
+	for _, rackTile := range state.GenerateAllRackTiles(playerState.rack) {
+		if !state.ValidCrossLetter(anchor, orientation, rackTile.tile.letter) {
+			continue
+		}
+		gaddagState := game.gaddag.Transition(rackTile.tile.letter)
+		if !gaddagState.Valid() {
+			continue
+		}
+		from := &gaddagMove{
+			anchor:      anchor,
+			orientation: orientation,
+			rack:        rackTile.rack,
+			state:       gaddagState,
+			leftPos:     anchor,
+			left:        MoveTiles{{Tile: rackTile.tile, pos: anchor, placedInMove: true}},
+			right:       MoveTiles{},
+		}
+		out = state.generateGaddagLeft(out, from)
+	}
+	return out
+}
+
+// generateGaddagLeft extends from towards the prefix direction. Board tiles must be taken - rack tiles may
+// be placed on empty squares which are not anchors (those moves are generated from the preceding anchor).
+func (state *GameState) generateGaddagLeft(out PartialMoves, from *gaddagMove) PartialMoves {
+	prefixDirection := from.orientation.PrefixDirection()
+	ok, pos := state.AdjacentPosition(from.leftPos, prefixDirection)
+	if !ok || state.IsTileEmpty(pos) {
+		// the left part is complete - the word so far is the reversed left part
+		if from.state.Final() {
+			ok, next := state.AdjacentPosition(from.anchor, from.orientation.SuffixDirection())
+			if !ok || state.IsTileEmpty(next) {
+				out = append(out, state.gaddagPartialMove(from))
+			}
+		}
+		separatorState := from.state.Transition(GaddagSeparator)
+		if separatorState.Valid() {
+			_, next := state.AdjacentPosition(from.anchor, from.orientation.SuffixDirection())
+			out = state.generateGaddagRight(out, from.extend(separatorState, from.rack, from.leftPos, nil), next)
+		}
+	}
+	if !ok {
+		return out
+	}
+	if !state.IsTileEmpty(pos) {
+		tile := state.tileBoard[pos.row][pos.column].Tile
+		toState := from.state.Transition(tile.letter)
+		if toState.Valid() {
+			out = state.generateGaddagLeft(out, from.extend(toState, from.rack, pos, &MoveTile{Tile: tile, pos: pos, placedInMove: false}))
+		}
+		return out
+	}
+	if state.IsAnchor(pos) {
+		return out
+	}
+	for _, rackTile := range state.GenerateAllRackTiles(from.rack) {
+		if !state.ValidCrossLetter(pos, from.orientation, rackTile.tile.letter) {
+			continue
+		}
+		toState := from.state.Transition(rackTile.tile.letter)
+		if toState.Valid() {
+			out = state.generateGaddagLeft(out, from.extend(toState, rackTile.rack, pos, &MoveTile{Tile: rackTile.tile, pos: pos, placedInMove: true}))
+		}
+	}
+	return out
+}
+
+// generateGaddagRight extends from in the suffix direction starting at pos
+func (state *GameState) generateGaddagRight(out PartialMoves, from *gaddagMove, pos Position) PartialMoves {
+	if !state.game.IsValidPos(pos) {
+		return out
+	}
+	suffixDirection := from.orientation.SuffixDirection()
+	emit := func(to *gaddagMove) {
+		if to.state.Final() {
+			ok, next := state.AdjacentPosition(pos, suffixDirection)
+			if !ok || state.IsTileEmpty(next) {
+				out = append(out, state.gaddagPartialMove(to))
+			}
+		}
+	}
+	_, next := state.AdjacentPosition(pos, suffixDirection)
+	if !state.IsTileEmpty(pos) {
+		tile := state.tileBoard[pos.row][pos.column].Tile
+		toState := from.state.Transition(tile.letter)
+		if toState.Valid() {
+			to := from.extendRight(toState, from.rack, MoveTile{Tile: tile, pos: pos, placedInMove: false})
+			emit(to)
+			out = state.generateGaddagRight(out, to, next)
+		}
+		return out
+	}
+	for _, rackTile := range state.GenerateAllRackTiles(from.rack) {
+		if !state.ValidCrossLetter(pos, from.orientation, rackTile.tile.letter) {
+			continue
+		}
+		toState := from.state.Transition(rackTile.tile.letter)
+		if toState.Valid() {
+			to := from.extendRight(toState, rackTile.rack, MoveTile{Tile: rackTile.tile, pos: pos, placedInMove: true})
+			emit(to)
+			out = state.generateGaddagRight(out, to, next)
+		}
+	}
+	return out
+}
+
+func (from *gaddagMove) extend(state DawgState, rack Rack, leftPos Position, tile *MoveTile) *gaddagMove {
+	to := *from
+	to.state = state
+	to.rack = rack
+	to.leftPos = leftPos
+	if tile != nil {
+		to.left = append(slices.Clip(from.left), *tile)
+	}
+	return &to
+}
+
+func (from *gaddagMove) extendRight(state DawgState, rack Rack, tile MoveTile) *gaddagMove {
+	to := *from
+	to.state = state
+	to.rack = rack
+	to.right = append(slices.Clip(from.right), tile)
+	return &to
+}
+
+// gaddagPartialMove converts a completed gaddag move to the PartialMove produced by the dawg move generator
+func (state *GameState) gaddagPartialMove(move *gaddagMove) *PartialMove {
+	game := state.game
+	tiles := make(MoveTiles, 0, len(move.left)+len(move.right))
+	for i := len(move.left) - 1; i >= 0; i-- {
+		tiles = append(tiles, move.left[i])
+	}
+	tiles = append(tiles, move.right...)
+	suffixDirection := move.orientation.SuffixDirection()
+	_, endPos := state.AdjacentPosition(tiles[len(tiles)-1].pos, suffixDirection)
+	pm := &PartialMove{
+		id:        state.NextMoveId(),
+		gameState: state,
+		rack:      move.rack,
+		startPos:  move.leftPos,
+		endPos:    endPos,
+		direction: suffixDirection,
+		state:     game.dawg.Transitions(game.TilesToWord(tiles.Tiles())),
+		tiles:     tiles,
+		score:     nil,
+	}
+	pm.Verify()
+	return pm
+}
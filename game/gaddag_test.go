@@ -0,0 +1,115 @@
+package game
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+
+	"golang.org/x/text/language"
+)
+
+func Test_GaddagMoves(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_GaddagMoves() failed to create corpus : %v", err)
+		return
+	}
+	content, err := newTestContent(corpus, "../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_GaddagMoves() failed to create corpus content : %v", err)
+		return
+	}
+	for seed := uint64(1); seed <= 3; seed++ {
+		options := &GameOptions{
+			Language: language.Danish,
+			RandSeed: seed,
+			Rand:     rand.New(rand.NewSource(int64(seed))),
+			Count:    1,
+			Out:      io.Discard,
+			Lexicon:  LEXICON_GADDAG,
+		}
+		g, err := newGame(options, int(seed), Players{BotPlayer(1), BotPlayer(2)}, content)
+		if err != nil {
+			t.Errorf("Test_GaddagMoves() failed to create game : %v", err)
+			return
+		}
+		game := g._Game()
+		for {
+			if !testGaddagMoves(t, game) {
+				return
+			}
+			if !game.Play() {
+				break
+			}
+		}
+	}
+}
+
+// testGaddagMoves compares the moves of both generators for the next player in game
+func testGaddagMoves(t *testing.T, game *_Game) bool {
+	curState := game.state
+	playerNo := curState.NextPlayer()
+	playerState := curState.playerStates[playerNo]
+	state := &GameState{
+		game:         game,
+		fromState:    curState,
+		tileBoard:    curState.tileBoard.Clone(),
+		playerStates: curState.playerStates,
+		playerNo:     playerNo,
+		freeTiles:    curState.freeTiles,
+	}
+	state.PrepareMove()
+	dawgMoves := partialMoveKeys(game, state.GenerateAllDawgMoves(playerState))
+	gaddagMoves := partialMoveKeys(game, state.GenerateAllGaddagMoves(playerState))
+	if !slices.Equal(dawgMoves, gaddagMoves) {
+		t.Errorf("game %d move %d rack %s : dawg generated %d moves and gaddag %d moves",
+			game.seqno, game.nextMoveSeqNo, playerState.rack.String(game.corpus), len(dawgMoves), len(gaddagMoves))
+		for _, m := range dawgMoves {
+			if _, found := slices.BinarySearch(gaddagMoves, m); !found {
+				t.Errorf("   only dawg   : %s", m)
+			}
+		}
+		for _, m := range gaddagMoves {
+			if _, found := slices.BinarySearch(dawgMoves, m); !found {
+				t.Errorf("   only gaddag : %s", m)
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func partialMoveKeys(game *_Game, moves PartialMoves) []string {
+	keys := make([]string, len(moves))
+	for i, m := range moves {
+		keys[i] = fmt.Sprintf("%s %s %s", m.startPos.String(), m.direction.String(), m.tiles.String(game.corpus))
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// newTestContent reads the words of fileName and adds each of them with four different first letters
+// so a board holding any letter can be extended
+func newTestContent(corpus Corpus, fileName string) (CorpusContent, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	words := make([]string, 0)
+	n := corpus.LetterMax() - 1
+	for i, w := range strings.Fields(string(data)) {
+		for j := 0; j < 4; j++ {
+			r := []rune(w)
+			words = append(words, corpus.LetterToString(Letter(1+(i+j*7)%n))+string(r[1:]))
+		}
+	}
+	slices.Sort(words)
+	words = slices.Compact(words)
+	return corpus.NewContent(strings.NewReader(strings.Join(words, "\n")))
+}
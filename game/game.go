@@ -9,9 +9,8 @@ import (
 	"golang.org/x/text/message"
 )
 
-const JOKER_COUNT = 2
-const WIDTH = 15
-const HEIGHT = 15
+const WIDTH = BOARD_DIMENSION
+const HEIGHT = BOARD_DIMENSION
 
 const MaxConsequtivePasses = 3
 
@@ -31,6 +30,14 @@ type Game interface {
 	Board() *Board
 	SquareCount() int
 	Play() bool
+	PlayBots()
+	PlayAction(playerNo PlayerNo, action Action) error
+	PreviewAction(playerNo PlayerNo, action Action) (*MovePreview, error)
+	NextPlayer() PlayerNo
+	Ended() bool
+	View(playerNo PlayerNo) *GameView
+	Moves() []MoveView
+	Header() *GameHeader
 	rand() *rand.Rand
 	_Game() *_Game
 }
@@ -43,7 +50,9 @@ type _Game struct {
 	fmt            *message.Printer
 	dimensions     Dimensions
 	corpus         Corpus
+	content        CorpusContent
 	dawg           Dawg
+	gaddag         Dawg
 	board          *Board
 	letterScores   LetterScores
 	players        []*Player
@@ -51,9 +60,20 @@ type _Game struct {
 	nextMoveSeqNo  uint
 	nextMoveId     uint
 	nextWriteSeqNo uint
+	ended          bool
+	resigned       PlayerNo // the player who resigned - NoPlayer when none did
+	messages       Messages // the result of the game when it has ended
 }
 
 func NewGame(options *GameOptions, seqno int, players Players, dimensions ...Coordinate) (Game, error) {
+	content, err := SharedDictionaryContent(options.Language, options.Dictionary)
+	if err != nil {
+		return nil, err
+	}
+	return newGame(options, seqno, players, content, dimensions...)
+}
+
+func newGame(options *GameOptions, seqno int, players Players, content CorpusContent, dimensions ...Coordinate) (Game, error) {
 	var width Coordinate
 	var height Coordinate
 	printer := message.NewPrinter(options.Language)
@@ -69,18 +89,17 @@ func NewGame(options *GameOptions, seqno int, players Players, dimensions ...Coo
 		width = dimensions[0]
 		height = dimensions[1]
 	}
-	var err error
-	corpus, err := NewCorpus(options.Language)
-	if err != nil {
-		return nil, err
-	}
-	content, err := corpus.GetLanguageContent()
+	corpus := content.Corpus()
+	dawg, err := SharedDawg(content, options.Options)
 	if err != nil {
 		return nil, err
 	}
-	dawg, err := NewDawg(content, options.Options)
-	if err != nil {
-		return nil, err
+	var gaddag Dawg
+	if options.Lexicon == LEXICON_GADDAG {
+		gaddag, err = SharedGaddag(content, options.Options)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	game := &_Game{
@@ -88,8 +107,10 @@ func NewGame(options *GameOptions, seqno int, players Players, dimensions ...Coo
 		seqno:         seqno,
 		dimensions:    Dimensions{Width: width, Height: height},
 		corpus:        corpus,
+		content:       content,
 		fmt:           printer,
 		dawg:          dawg,
+		gaddag:        gaddag,
 		board:         nil,
 		letterScores:  make(LetterScores, corpus.LetterMax()),
 		players:       make(Players, len(players)+1),
@@ -120,6 +141,15 @@ func NewGame(options *GameOptions, seqno int, players Players, dimensions ...Coo
 	return game, err
 }
 
+// NewLetterScores returns the score of each letter of corpus as given by the language tiles
+func NewLetterScores(corpus Corpus) LetterScores {
+	letterScores := make(LetterScores, corpus.LetterMax())
+	for _, tile := range GetLanguageTiles(corpus.Language()) {
+		letterScores[corpus.StringToLetter(tile.Character())] = Score(tile.Value())
+	}
+	return letterScores
+}
+
 func (game *_Game) _Game() *_Game {
 	return game
 }
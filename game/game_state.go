@@ -6,6 +6,7 @@ import (
 	"strings"
 	. "wordfeud/corpus"
 	. "wordfeud/dawg"
+	. "wordfeud/localize"
 
 	"golang.org/x/text/language"
 )
@@ -112,14 +113,14 @@ func initialGameState(game *_Game) (*GameState, error) {
 	allLetters := game.corpus.AllLetters()
 
 	languageTiles := GetLanguageTiles(options.Language)
+	game.letterScores = NewLetterScores(corpus)
 	for _, tile := range languageTiles {
-		game.letterScores[game.corpus.RuneToLetter(tile.Character())] = Score(tile.Value())
 		state.freeTiles = slices.Grow(state.freeTiles, len(state.freeTiles)+int(tile.Count()))
 		for i, n := byte(0), byte(tile.Count()); i < n; i++ {
-			state.freeTiles = append(state.freeTiles, Tile{TILE_LETTER, corpus.RuneToLetter(tile.Character())})
+			state.freeTiles = append(state.freeTiles, Tile{TILE_LETTER, corpus.StringToLetter(tile.Character())})
 		}
 	}
-	for i := 0; byte(i) < JOKER_COUNT; i++ {
+	for i := 0; i < GetLanguageBlanks(options.Language); i++ {
 		state.freeTiles = append(state.freeTiles, Tile{TILE_JOKER, 0})
 	}
 
@@ -383,19 +384,7 @@ func (orientation Orientation) String() string {
 
 }
 func (orientation Orientation) Localized(lang language.Tag) string {
-	switch lang {
-	case language.Danish:
-		switch orientation {
-		case HORIZONTAL:
-			return "horisontalt"
-		case VERTICAL:
-			return "vertikalt"
-		}
-	default:
-		return orientation.String()
-	}
-	panic(fmt.Sprintf("invalid orientation %d", orientation))
-
+	return Localized(lang, orientation.String())
 }
 
 func (dir Direction) String() string {
@@ -416,7 +405,7 @@ func (dir Direction) String() string {
 
 func (dir Direction) Localized(lang language.Tag) string {
 	switch lang {
-	case language.Danish:
+	case language.Danish, NorwegianBokmal:
 		switch dir {
 		case NONE:
 			return "INGEN"
@@ -429,6 +418,19 @@ func (dir Direction) Localized(lang language.Tag) string {
 		case WEST:
 			return "V"
 		}
+	case language.Swedish:
+		switch dir {
+		case NONE:
+			return "INGEN"
+		case NORTH:
+			return "N"
+		case SOUTH:
+			return "S"
+		case EAST:
+			return "Ö"
+		case WEST:
+			return "V"
+		}
 	default:
 		return dir.String()
 	}
@@ -654,7 +656,7 @@ func (state *GameState) TilesToString(tiles Tiles) string {
 	for _, t := range tiles {
 		switch t.kind {
 		case TILE_LETTER, TILE_JOKER:
-			sb.WriteRune(corpus.LetterToRune(t.letter))
+			sb.WriteString(corpus.LetterToString(t.letter))
 		}
 	}
 	return sb.String()
@@ -0,0 +1,182 @@
+package game
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	. "wordfeud/corpus"
+	. "wordfeud/dawg"
+)
+
+// GameHeader identifies the dictionary and the rules a game was played with
+type GameHeader struct {
+	Game        string `json:"game"`
+	Language    string `json:"language"`
+	Dictionary  string `json:"dictionary"`  // the dictionary spec - empty for the language corpus file
+	Fingerprint string `json:"fingerprint"` // the checksum of the words of the dictionary and its overlay (and so of its dawg)
+	Ruleset     string `json:"ruleset"`     // the language and a hash of the tiles, the board and the rack size - see Ruleset
+}
+
+// the keys of the header lines of text game files and of the meta tags of html game files
+const (
+	HEADER_GAME        = "game"
+	HEADER_LANGUAGE    = "language"
+	HEADER_DICTIONARY  = "dictionary"
+	HEADER_FINGERPRINT = "fingerprint"
+	HEADER_RULESET     = "ruleset"
+)
+
+// the number of lines at the start of a text game file searched for the header
+const headerLineMax = 20
+
+func (game *_Game) Header() *GameHeader {
+	return &GameHeader{
+		Game:        fmt.Sprintf("%s-%d", game.options.Name, game.seqno),
+		Language:    game.corpus.Language().String(),
+		Dictionary:  game.options.Dictionary,
+		Fingerprint: game.Fingerprint(),
+		Ruleset:     game.Ruleset(),
+	}
+}
+
+// Fingerprint returns the checksum of the dictionary of game - the checksum of its corpus content
+// or, when words are added to or removed from the dawg by an overlay, a hash of the checksum and the overlay
+func (game *_Game) Fingerprint() string {
+	checksum := game.content.Checksum()
+	overlayDawg, ok := game.dawg.(OverlayDawg)
+	if !ok {
+		return checksum.String()
+	}
+	overlay := overlayDawg.Overlay()
+	if overlay.Empty() {
+		return checksum.String()
+	}
+	h := sha256.New()
+	h.Write(checksum[:])
+	for _, word := range overlay.Added {
+		fmt.Fprintf(h, "+%s\n", word.String(game.corpus))
+	}
+	for _, word := range overlay.Removed {
+		fmt.Fprintf(h, "-%s\n", word.String(game.corpus))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Ruleset returns the identifier of the rules of game - the language followed by a hash of the
+// tiles of the language, the board dimensions, the number of each special square and the rack size
+// - the positions of the special squares are random and not part of the rules
+func (game *_Game) Ruleset() string {
+	lang := game.corpus.Language()
+	h := sha256.New()
+	fmt.Fprintf(h, "language %s\n", lang.String())
+	fmt.Fprintf(h, "blanks %d\n", GetLanguageBlanks(lang))
+	for _, tile := range GetLanguageTiles(lang) {
+		fmt.Fprintf(h, "tile %s %d %d\n", tile.Character(), tile.Count(), tile.Value())
+	}
+	fmt.Fprintf(h, "board %d %d\n", game.dimensions.Width, game.dimensions.Height)
+	for _, f := range specialFields {
+		fmt.Fprintf(h, "square %d %d\n", f.kind, f.count)
+	}
+	fmt.Fprintf(h, "rack %d\n", RackSize)
+	return lang.String() + "-" + hex.EncodeToString(h.Sum(nil)[:4])
+}
+
+// entries returns the header as key value pairs in the order written to game files
+func (header *GameHeader) entries() [][2]string {
+	return [][2]string{
+		{HEADER_GAME, header.Game},
+		{HEADER_LANGUAGE, header.Language},
+		{HEADER_DICTIONARY, header.Dictionary},
+		{HEADER_FINGERPRINT, header.Fingerprint},
+		{HEADER_RULESET, header.Ruleset},
+	}
+}
+
+func (header *GameHeader) set(key string, value string) {
+	switch key {
+	case HEADER_GAME:
+		header.Game = value
+	case HEADER_LANGUAGE:
+		header.Language = value
+	case HEADER_DICTIONARY:
+		header.Dictionary = value
+	case HEADER_FINGERPRINT:
+		header.Fingerprint = value
+	case HEADER_RULESET:
+		header.Ruleset = value
+	}
+}
+
+// Check returns an error when the game of header was played with another dictionary or other rules than game
+func (header *GameHeader) Check(game Game) error {
+	current := game._Game().Header()
+	if header.Fingerprint == "" {
+		return fmt.Errorf("game %s has no dictionary fingerprint - it was written before fingerprints were recorded", header.Game)
+	}
+	if header.Fingerprint != current.Fingerprint {
+		return fmt.Errorf("game %s was played with dictionary \"%s\" fingerprint %s - the dictionary is now fingerprint %s",
+			header.Game, header.Dictionary, header.Fingerprint, current.Fingerprint)
+	}
+	if header.Ruleset != current.Ruleset {
+		return fmt.Errorf("game %s was played with ruleset %s - the ruleset is now %s", header.Game, header.Ruleset, current.Ruleset)
+	}
+	return nil
+}
+
+var htmlHeaderRegexp = regexp.MustCompile(`<meta name="wordfeud-(\w+)" content="([^"]*)">`)
+
+// ReadGameHeader reads the header of the game file fileName - a text, json or html game file
+// (the directory of the html files or its index.html)
+func ReadGameHeader(fileName string) (*GameHeader, error) {
+	if info, err := os.Stat(fileName); err == nil && info.IsDir() {
+		fileName = path.Join(fileName, "index.html")
+	}
+	header := new(GameHeader)
+	switch path.Ext(fileName) {
+	case ".json":
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		file := struct {
+			Header *GameHeader `json:"header"`
+		}{Header: header}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("game file \"%s\" : %v", fileName, err)
+		}
+	case ".html":
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range htmlHeaderRegexp.FindAllStringSubmatch(string(data), -1) {
+			header.set(match[1], html.UnescapeString(match[2]))
+		}
+	default:
+		f, err := os.Open(fileName)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		s := bufio.NewScanner(f)
+		for n := 0; n < headerLineMax && s.Scan(); n++ {
+			if key, value, found := strings.Cut(s.Text(), ":"); found {
+				header.set(strings.TrimSpace(key), strings.TrimSpace(value))
+			}
+		}
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if header.Game == "" {
+		header.Game = fileName
+	}
+	return header, nil
+}
@@ -0,0 +1,121 @@
+package game
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+	. "wordfeud/dawg"
+
+	"golang.org/x/text/language"
+)
+
+func Test_GameHeader(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_GameHeader() failed to create corpus : %v", err)
+		return
+	}
+	content, err := newTestContent(corpus, "../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_GameHeader() failed to create corpus content : %v", err)
+		return
+	}
+	directory := t.TempDir()
+	options := &GameOptions{
+		Name:      "header",
+		Language:  language.Danish,
+		RandSeed:  1,
+		Rand:      rand.New(rand.NewSource(1)),
+		Count:     1,
+		Out:       io.Discard,
+		Directory: directory,
+		File:      path.Join(directory, "header"),
+	}
+	g, err := newGame(options, 1, BotPlayers(options, 2), content)
+	if err != nil {
+		t.Errorf("Test_GameHeader() failed to create game : %v", err)
+		return
+	}
+	for g.Play() {
+	}
+	// the same words but the first
+	words := make([]string, 0, content.WordCount())
+	for _, word := range content.Words()[1:] {
+		words = append(words, word.String(corpus))
+	}
+	other, err := corpus.NewContent(strings.NewReader(strings.Join(words, "\n")))
+	if err != nil {
+		t.Errorf("Test_GameHeader() failed to create corpus content : %v", err)
+		return
+	}
+	otherGame, err := newGame(options, 2, BotPlayers(options, 2), other)
+	if err != nil {
+		t.Errorf("Test_GameHeader() failed to create game : %v", err)
+		return
+	}
+
+	expected := g._Game().Header()
+	for _, format := range []FileFormat{FILE_FORMAT_TEXT, FILE_FORMAT_JSON, FILE_FORMAT_HTML} {
+		options.FileFormat = format
+		fileName, err := WriteGameFile(g, true, Messages{})
+		if err != nil {
+			t.Errorf("Test_GameHeader() failed to write %s game file : %v", format.String(), err)
+			continue
+		}
+		header, err := ReadGameHeader(fileName)
+		if err != nil {
+			t.Errorf("Test_GameHeader() failed to read %s game file header : %v", format.String(), err)
+			continue
+		}
+		if *header != *expected {
+			t.Errorf("Test_GameHeader() %s game file header %v expected %v", format.String(), *header, *expected)
+		}
+		if err := header.Check(g); err != nil {
+			t.Errorf("Test_GameHeader() %s game file does not match its own game : %v", format.String(), err)
+		}
+		if err := header.Check(otherGame); err == nil {
+			t.Errorf("Test_GameHeader() %s game file matches a game of another dictionary", format.String())
+		}
+	}
+
+	options.FileFormat = FILE_FORMAT_JSON
+	fileName, err := WriteGameFile(g, true, Messages{})
+	if err != nil {
+		t.Errorf("Test_GameHeader() failed to write json game file : %v", err)
+		return
+	}
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Errorf("Test_GameHeader() failed to open json game file : %v", err)
+		return
+	}
+	defer f.Close()
+	header, words, err := ReadGameFileJson(f)
+	if err != nil || header == nil || *header != *expected {
+		t.Errorf("Test_GameHeader() failed to read json game file : %v", err)
+	}
+	played := make([]string, 0)
+	for _, move := range g.Moves() {
+		if move.Kind == MOVE_PLACE.String() {
+			played = append(played, move.Word)
+		}
+	}
+	if len(played) == 0 || strings.Join(words, " ") != strings.Join(played, " ") {
+		t.Errorf("Test_GameHeader() the words of the json game file are %v - expected %v", words, played)
+	}
+
+	// the words of an overlay are part of the fingerprint
+	_game := g._Game()
+	_game.dawg = NewOverlayDawg(_game.dawg, Overlay{Removed: Words{content.Words()[0]}})
+	if _game.Header().Fingerprint == expected.Fingerprint {
+		t.Errorf("Test_GameHeader() a word removed by an overlay does not change the fingerprint %s", expected.Fingerprint)
+	}
+	if err := expected.Check(g); err == nil {
+		t.Errorf("Test_GameHeader() the header matches the game with an overlay")
+	}
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+	. "wordfeud/localize"
+
+	"golang.org/x/text/language"
+)
+
+func Test_PlayLanguages(t *testing.T) {
+	tests := []struct {
+		lang     language.Tag
+		fileName string
+	}{
+		{language.English, "../data_test/corpus_en_test.txt"},
+		{language.Swedish, "../data_test/corpus_sv_test.txt"},
+		{NorwegianBokmal, "../data_test/corpus_nb_test.txt"},
+	}
+	for _, test := range tests {
+		corpus, err := NewCorpus(test.lang)
+		if err != nil {
+			t.Errorf("Test_PlayLanguages(%s) failed to create corpus : %v", test.lang, err)
+			continue
+		}
+		content, err := corpus.GetFileContent(test.fileName)
+		if err != nil {
+			t.Errorf("Test_PlayLanguages(%s) failed to create corpus content : %v", test.lang, err)
+			continue
+		}
+		options := &GameOptions{
+			Language: test.lang,
+			RandSeed: 1,
+			Rand:     rand.New(rand.NewSource(1)),
+			Count:    1,
+			Out:      io.Discard,
+		}
+		g, err := newGame(options, 1, Players{BotPlayer(1), BotPlayer(2)}, content)
+		if err != nil {
+			t.Errorf("Test_PlayLanguages(%s) failed to create game : %v", test.lang, err)
+			continue
+		}
+		game := g._Game()
+		for game.Play() {
+		}
+		if game.nextMoveSeqNo < 2 {
+			t.Errorf("Test_PlayLanguages(%s) game ended after %d moves", test.lang, game.nextMoveSeqNo)
+		}
+		var sb strings.Builder
+		if err = WriteGameFileText(&sb, game, game.ResultMessages()); err != nil {
+			t.Errorf("Test_PlayLanguages(%s) failed to write game : %v", test.lang, err)
+			continue
+		}
+		text := sb.String()
+		for _, s := range []string{Localized(test.lang, "Scrabble game"), Localized(test.lang, "horizontal")} {
+			if !strings.Contains(text, s) {
+				t.Errorf("Test_PlayLanguages(%s) game file does not contain \"%s\"", test.lang, s)
+			}
+		}
+	}
+}
+
+func Benchmark_PlayDanish(b *testing.B) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		b.Fatalf("Benchmark_PlayDanish() failed to create corpus : %v", err)
+	}
+	content, err := newTestContent(corpus, "../data_test/dk_partial.txt")
+	if err != nil {
+		b.Fatalf("Benchmark_PlayDanish() failed to create corpus content : %v", err)
+	}
+	for i := 0; i < b.N; i++ {
+		seed := uint64(i%3 + 1)
+		options := &GameOptions{
+			Language: language.Danish,
+			RandSeed: seed,
+			Rand:     rand.New(rand.NewSource(int64(seed))),
+			Count:    1,
+			Out:      io.Discard,
+		}
+		g, err := newGame(options, int(seed), Players{BotPlayer(1), BotPlayer(2)}, content)
+		if err != nil {
+			b.Fatalf("Benchmark_PlayDanish() failed to create game : %v", err)
+		}
+		game := g._Game()
+		for game.Play() {
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	. "wordfeud/context"
+)
+
+// botLevelRule limits the words a bot player knows and how well it chooses among its moves
+type botLevelRule struct {
+	maxRank    int     // the highest frequency rank of the words played - 0 for all words
+	percentile float64 // the position of the chosen move among the moves ordered by score - 0 is the best move
+	spread     float64 // the largest random deviation from the percentile
+}
+
+var botLevelRules = map[BotLevel]botLevelRule{
+	BOT_LEVEL_EXPERT:   {maxRank: 0, percentile: 0, spread: 0},
+	BOT_LEVEL_STRONG:   {maxRank: 0, percentile: 0.1, spread: 0.1},
+	BOT_LEVEL_CASUAL:   {maxRank: 20000, percentile: 0.3, spread: 0.2},
+	BOT_LEVEL_BEGINNER: {maxRank: 5000, percentile: 0.5, spread: 0.25},
+}
+
+// FilterLevelMove returns the move chosen by a bot player at level among allMoves
+// the moves are restricted to those of words ranked within the vocabulary of the level
+// when the corpus has frequency ranks and any such move exists
+func (state *GameState) FilterLevelMove(allMoves PartialMoves, level BotLevel) PartialMoves {
+	rule, found := botLevelRules[level]
+	if !found {
+		panic(fmt.Sprintf("illegal BotLevel %d (FilterLevelMove)", level))
+	}
+	if rule.percentile == 0 && rule.maxRank == 0 {
+		return state.FilterBestMove(allMoves)
+	}
+	moves := make(PartialMoves, 0, len(allMoves))
+	for _, move := range allMoves {
+		if move.score == nil {
+			move.score = state.CalcScore(move.tiles, move.direction.Orientation())
+		}
+		if state.knownWords(move, rule.maxRank) {
+			moves = append(moves, move)
+		}
+	}
+	if len(moves) == 0 {
+		moves = slices.Clone(allMoves)
+	}
+	if len(moves) == 0 {
+		return moves
+	}
+	slices.SortStableFunc(moves, func(a, b *PartialMove) int {
+		return cmp.Compare(b.score.score, a.score.score)
+	})
+
+	position := rule.percentile + rule.spread*(2*state.game.rand().Float64()-1)
+	position = min(max(position, 0), 1)
+	chosen := moves[int(position*float64(len(moves)-1)+0.5)]
+	if state.game.options.Debug > 0 {
+		fmt.Printf("\n\nFilterLevelMove %s: %d of %d moves, position %.2f score %v\n",
+			level.String(), len(moves), len(allMoves), position, chosen.score.score)
+		PrintPartialMove(chosen, "")
+	}
+	return PartialMoves{chosen}
+}
+
+// knownWords reports whether all the words formed by move have a frequency rank within maxRank
+func (state *GameState) knownWords(move *PartialMove, maxRank int) bool {
+	if maxRank == 0 {
+		return true
+	}
+	game := state.game
+	for _, wordScore := range move.score.wordScores {
+		tiles := make(Tiles, len(wordScore.tileScores))
+		for i, tileScore := range wordScore.tileScores {
+			tiles[i] = tileScore.tile.Tile
+		}
+		metadata, found := game.content.Metadata(game.TilesToWord(tiles))
+		if !found || metadata.FrequencyRank == 0 || metadata.FrequencyRank > maxRank {
+			return false
+		}
+	}
+	return true
+}
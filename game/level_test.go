@@ -0,0 +1,113 @@
+package game
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+
+	"golang.org/x/text/language"
+)
+
+func Test_BotLevels(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_BotLevels() failed to create corpus : %v", err)
+		return
+	}
+	partial, err := newTestContent(corpus, "../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_BotLevels() failed to create corpus content : %v", err)
+		return
+	}
+	// every other word is among the 5000 most frequent words
+	words := make([]string, 0)
+	metadata := make(Metadata)
+	for i, word := range partial.Words() {
+		w := word.String(corpus)
+		words = append(words, w)
+		metadata[w] = WordMetadata{FrequencyRank: 1 + i%2*10000 + i}
+	}
+	fileName := path.Join(t.TempDir(), "corpus_da.txt")
+	if err := os.WriteFile(fileName, []byte(strings.Join(words, "\n")+"\n"), 0644); err != nil {
+		t.Errorf("Test_BotLevels() failed to write corpus : %v", err)
+		return
+	}
+	if err := WriteMetadataFile(MetadataFileName(fileName), metadata); err != nil {
+		t.Errorf("Test_BotLevels() failed to write metadata : %v", err)
+		return
+	}
+	content, err := corpus.GetFileContent(fileName)
+	if err != nil {
+		t.Errorf("Test_BotLevels() failed to read corpus content : %v", err)
+		return
+	}
+
+	options := &GameOptions{
+		Language:  language.Danish,
+		RandSeed:  1,
+		Rand:      rand.New(rand.NewSource(1)),
+		Count:     1,
+		Out:       io.Discard,
+		BotLevels: BotLevels{BOT_LEVEL_BEGINNER, BOT_LEVEL_EXPERT},
+	}
+	g, err := newGame(options, 1, BotPlayers(options, 2), content)
+	if err != nil {
+		t.Errorf("Test_BotLevels() failed to create game : %v", err)
+		return
+	}
+	game := g._Game()
+	lower := 0
+	for {
+		allMoves, state := nextMoves(game)
+		if !game.Play() {
+			break
+		}
+		move := game.state.move
+		best := state.FilterBestMove(allMoves)
+		if len(best) == 0 {
+			continue
+		}
+		switch move.playerState.player.Level() {
+		case BOT_LEVEL_EXPERT:
+			if move.score.score != best[0].score.score {
+				t.Errorf("move %d : expert scored %d - the best move scores %d", move.seqno, move.score.score, best[0].score.score)
+			}
+		case BOT_LEVEL_BEGINNER:
+			if move.score.score < best[0].score.score {
+				lower++
+			}
+			known := false
+			for _, m := range allMoves {
+				known = known || state.knownWords(m, 5000)
+			}
+			if known && !state.knownWords(&PartialMove{score: move.score}, 5000) {
+				t.Errorf("move %d : beginner played \"%s\" which is not among the most frequent words",
+					move.seqno, move.tiles.String(game.corpus))
+			}
+		}
+	}
+	if lower == 0 {
+		t.Errorf("Test_BotLevels() the beginner always played the best move")
+	}
+}
+
+// nextMoves returns all the moves of the next player in game
+func nextMoves(game *_Game) (PartialMoves, *GameState) {
+	curState := game.state
+	playerNo := curState.NextPlayer()
+	state := &GameState{
+		game:         game,
+		fromState:    curState,
+		tileBoard:    curState.tileBoard.Clone(),
+		playerStates: curState.playerStates,
+		playerNo:     playerNo,
+		freeTiles:    curState.freeTiles,
+	}
+	state.PrepareMove()
+	return state.GenerateAllMoves(curState.playerStates[playerNo]), state
+}
@@ -36,8 +36,20 @@ type MoveTile struct {
 }
 
 type MoveTiles []MoveTile
+
+// MoveKind tells how a player used a turn
+type MoveKind byte
+
+const (
+	MOVE_PLACE    = MoveKind(0)
+	MOVE_PASS     = MoveKind(1)
+	MOVE_EXCHANGE = MoveKind(2)
+	MOVE_RESIGN   = MoveKind(3)
+)
+
 type Move struct {
 	id          uint
+	kind        MoveKind
 	seqno       uint
 	state       *GameState
 	playerState *PlayerState
@@ -45,6 +57,7 @@ type Move struct {
 	direction   Direction
 	tiles       MoveTiles
 	score       *MoveScore
+	exchanged   int // the number of tiles exchanged by a move of kind MOVE_EXCHANGE
 }
 
 func (state *GameState) NewMove(position Position, direction Direction, tiles MoveTiles, moveScore *MoveScore, playerState *PlayerState) *Move {
@@ -189,7 +202,7 @@ func (state *GameState) Move(playerState *PlayerState) *Move {
 	}
 	state.PrepareMove()
 
-	filteredPartialMoves := state.FilterBestMove(state.GenerateAllMoves(playerState))
+	filteredPartialMoves := state.FilterLevelMove(state.GenerateAllMoves(playerState), playerState.player.level)
 
 	if len(filteredPartialMoves) == 0 {
 		return nil
@@ -222,6 +235,7 @@ func (state *GameState) AddPass(playerState *PlayerState) *Move {
 			score:    playerState.score,
 			rack:     playerState.rack,
 		})
+	move.kind = MOVE_PASS
 	state.playerStates[move.playerState.playerNo] = move.playerState
 	move.state = state
 	state.move = move
@@ -44,15 +44,24 @@ var AllMessageCategories = MessageCategories{MESSAGE_RESULT, MESSAGE_DETAIL}
 type Messages map[MessageCategory][]string
 
 func (game *_Game) Play() bool {
-	options := game.options
-	lang := game.options.Language
-	p := game.fmt
+	if game.ended {
+		return false
+	}
+	state, playerState := game.nextState()
+	move := state.Move(playerState)
+	if move == nil {
+		return false
+	}
+	return game.completeMove(state, playerState)
+}
 
+// nextState returns the state for the move of the next player and the state of the player
+func (game *_Game) nextState() (*GameState, *PlayerState) {
+	options := game.options
 	curState := game.state
 	playerNo := curState.NextPlayer()
 	curPlayerStates := curState.playerStates
 	curPlayerState := curPlayerStates[playerNo]
-	result := false
 
 	if curState.move == nil && options.Move > 0 {
 		options.MoveDebug = options.Debug
@@ -75,73 +84,85 @@ func (game *_Game) Play() bool {
 		freeTiles:         slices.Clone(curState.freeTiles),
 		consequtivePasses: curState.consequtivePasses,
 	}
-	move := state.Move(playerState)
-	if move != nil {
-		state.move = move
-		game.state = state // == move.state
-		messages := make(Messages)
-		result = true
+	return state, playerState
+}
 
-		for _, ps := range game.state.playerStates {
-			state.FillRack(ps)
-		}
+// completeMove makes state (holding the move of playerState) the state of game, refills the racks and
+// checks whether the game has ended - it returns true while the game goes on
+func (game *_Game) completeMove(state *GameState, playerState *PlayerState) bool {
+	options := game.options
+	lang := game.options.Language
+	p := game.fmt
+	move := state.move
+	game.state = state // == move.state
+	messages := make(Messages)
+	result := true
 
-		if options.Debug > 0 {
-			p.Printf("game play completed move : %s\n", playerState.String(game.corpus))
-		}
-		for _, ps := range game.state.playerStates {
-			if ps.playerNo != NoPlayer && ps.NumberOfRackTiles() == 0 {
-				messages.addMessage(MESSAGE_RESULT, fmt.Sprintf(Localized(lang, "Game completed after %d moves as %s has no more tiles in rack"), state.move.seqno, ps.player.name))
-				result = false
-				break
-			}
-		}
-		if result && state.consequtivePasses >= MaxConsequtivePasses {
-			messages.addMessage(MESSAGE_RESULT, fmt.Sprintf(Localized(lang, "Game completed after %d moves as there has been %d conequtive passes"), state.move.seqno, state.consequtivePasses))
+	for _, ps := range game.state.playerStates {
+		state.FillRack(ps)
+	}
+
+	if options.Debug > 0 {
+		p.Printf("game play completed move : %s\n", playerState.String(game.corpus))
+	}
+	if move.kind == MOVE_RESIGN {
+		messages.addMessage(MESSAGE_RESULT, fmt.Sprintf(Localized(lang, "Game completed after %d moves as %s resigned"), move.seqno, playerState.player.name))
+		result = false
+	}
+	for _, ps := range game.state.playerStates {
+		if result && ps.playerNo != NoPlayer && ps.NumberOfRackTiles() == 0 {
+			messages.addMessage(MESSAGE_RESULT, fmt.Sprintf(Localized(lang, "Game completed after %d moves as %s has no more tiles in rack"), state.move.seqno, ps.player.name))
 			result = false
+			break
 		}
+	}
+	if result && state.consequtivePasses >= MaxConsequtivePasses {
+		messages.addMessage(MESSAGE_RESULT, fmt.Sprintf(Localized(lang, "Game completed after %d moves as there has been %d conequtive passes"), state.move.seqno, state.consequtivePasses))
+		result = false
+	}
 
-		if !result {
-			messages.addMessages(game.ResultMessages())
-			messages.addMessage(MESSAGE_DETAIL, p.Sprintf("%s %d", Localized(lang, "Random number generator seed:"), game.RandSeed))
-			messages.addMessage(MESSAGE_DETAIL, p.Sprintf("%s %d", Localized(lang, "Number of moves in game:"), game.nextMoveSeqNo-1))
-			messages.addMessage(MESSAGE_DETAIL, p.Sprintf(Localized(lang, "Remaining free tiles:")+" (%d) %s",
-				len(game.state.freeTiles), game.state.freeTiles.String(game.corpus)))
-		}
+	if !result {
+		game.ended = true
+		messages.addMessages(game.ResultMessages())
+		messages.addMessage(MESSAGE_DETAIL, p.Sprintf("%s %d", Localized(lang, "Random number generator seed:"), game.RandSeed))
+		messages.addMessage(MESSAGE_DETAIL, p.Sprintf("%s %d", Localized(lang, "Number of moves in game:"), game.nextMoveSeqNo-1))
+		messages.addMessage(MESSAGE_DETAIL, p.Sprintf(Localized(lang, "Remaining free tiles:")+" (%d) %s",
+			len(game.state.freeTiles), game.state.freeTiles.String(game.corpus)))
+		game.messages = messages
+	}
 
-		if options.WriteFile {
-			gameFileName, err := WriteGameFile(game, !result, messages)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "error writing game file \"%s\"\n%v\n", gameFileName, err.Error())
-				return false
-			}
-			if options.Verbose {
-				messages.addMessage(MESSAGE_DETAIL,
-					fmt.Sprintf(Localized(lang, "Wrote game file after move %d \"%s\""),
-						game.nextMoveSeqNo-1, gameFileName))
-			} else {
-				if !result {
-					messages.addMessage(MESSAGE_DETAIL, fmt.Sprintf(Localized(lang, "Game file is %s"), gameFileName))
-				}
+	if options.WriteFile {
+		gameFileName, err := WriteGameFile(game, !result, messages)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error writing game file \"%s\"\n%v\n", gameFileName, err.Error())
+			return false
+		}
+		if options.Verbose {
+			messages.addMessage(MESSAGE_DETAIL,
+				fmt.Sprintf(Localized(lang, "Wrote game file after move %d \"%s\""),
+					game.nextMoveSeqNo-1, gameFileName))
+		} else {
+			if !result {
+				messages.addMessage(MESSAGE_DETAIL, fmt.Sprintf(Localized(lang, "Game file is %s"), gameFileName))
 			}
 		}
+	}
 
-		if !result && len(messages) > 0 {
-			fmt.Println("")
+	if !result && len(messages) > 0 {
+		fmt.Println("")
 
-			for _, category := range AllMessageCategories {
-				for _, m := range messages[category] {
-					fmt.Println(m)
+		for _, category := range AllMessageCategories {
+			for _, m := range messages[category] {
+				fmt.Println(m)
 
-				}
-				fmt.Println("")
 			}
-		}
-		if options.Move > 0 && move.seqno >= options.Move {
-			options.Debug = options.MoveDebug
-			options.Move = 0
+			fmt.Println("")
 		}
 	}
+	if options.Move > 0 && move.seqno >= options.Move {
+		options.Debug = options.MoveDebug
+		options.Move = 0
+	}
 	return result
 }
 
@@ -154,7 +175,7 @@ func (game *_Game) ResultMessages() Messages {
 	for _, ps := range game.state.playerStates {
 		if ps.playerNo != NoPlayer {
 			allPlayers = append(allPlayers, ps)
-			if ps.score > bestScore {
+			if ps.playerNo != game.resigned && ps.score > bestScore {
 				bestScore = ps.score
 			}
 		}
@@ -168,6 +189,9 @@ func (game *_Game) ResultMessages() Messages {
 		if ps.score < bestScore {
 			break
 		}
+		if ps.playerNo == game.resigned {
+			continue
+		}
 		bestScorePlayerNames = append(bestScorePlayerNames, ps.player.name)
 	}
 	if len(bestScorePlayerNames) > 1 {
@@ -279,6 +303,13 @@ func (state *GameState) PrepareMove() {
 }
 
 func (state *GameState) GenerateAllMoves(playerState *PlayerState) PartialMoves {
+	if state.game.gaddag != nil {
+		return state.GenerateAllGaddagMoves(playerState)
+	}
+	return state.GenerateAllDawgMoves(playerState)
+}
+
+func (state *GameState) GenerateAllDawgMoves(playerState *PlayerState) PartialMoves {
 	options := state.game.options
 	corpus := state.game.corpus
 	if options.Debug > 0 {
@@ -554,8 +585,13 @@ func (state *GameState) GeneratePrefixes(from *PartialMove, length Coordinate) P
 }
 
 func (state *GameState) GenerateAllRackTiles(rack Rack) RackTiles {
+	return rack.RackTiles(state.game.corpus)
+}
+
+// RackTiles returns each distinct tile which can be taken from rack together with the rest of the rack.
+// A joker is returned once for every letter of the corpus.
+func (rack Rack) RackTiles(corpus Corpus) RackTiles {
 	out := make(RackTiles, 0, 10)
-	corpus := state.game.corpus
 	for i, tile := range rack {
 		if slices.ContainsFunc(rack[:i], func(t Tile) bool { return t.equal(tile) }) {
 			continue
@@ -1,11 +1,18 @@
 package game
 
+import (
+	"sync"
+	. "wordfeud/context"
+)
+
 type PlayerNo uint8
 type PlayerId uint
 
 type Player struct {
-	id   PlayerId
-	name string
+	id    PlayerId
+	name  string
+	level BotLevel
+	human bool // the moves are made by a person - not generated
 }
 
 type Players []*Player
@@ -27,7 +34,10 @@ var BotPlayerNames = [MaxBotPlayers]string{
 	"*Karen*",
 }
 
-var nextPlayerId = 1000
+var nextPlayerId = struct {
+	sync.Mutex
+	id PlayerId
+}{id: 1000}
 
 var SystemPlayer = &Player{id: SystemPlayerId, name: "__SYSTEM__"}
 
@@ -43,3 +53,41 @@ func BotPlayer(no PlayerNo) *Player {
 	}
 	return botPlayers[no]
 }
+
+// LevelBotPlayer returns bot player no playing at level
+func LevelBotPlayer(no PlayerNo, level BotLevel) *Player {
+	bot := BotPlayer(no)
+	if bot == nil || level == bot.level {
+		return bot
+	}
+	return &Player{id: bot.id, name: bot.name, level: level}
+}
+
+// BotPlayers returns the count bot players at the levels of options
+func BotPlayers(options *GameOptions, count int) Players {
+	players := make(Players, count)
+	for i := range players {
+		players[i] = LevelBotPlayer(PlayerNo(i+1), options.BotLevels.Level(i+1))
+	}
+	return players
+}
+
+func (player *Player) Level() BotLevel {
+	return player.level
+}
+
+// HumanPlayer returns a new player named name whose moves are made by a person
+func HumanPlayer(name string) *Player {
+	nextPlayerId.Lock()
+	defer nextPlayerId.Unlock()
+	nextPlayerId.id++
+	return &Player{id: nextPlayerId.id, name: name, human: true}
+}
+
+func (player *Player) Name() string {
+	return player.name
+}
+
+func (player *Player) IsHuman() bool {
+	return player.human
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"unicode"
+	"strings"
 	. "wordfeud/corpus"
 )
 
@@ -189,15 +189,15 @@ func FprintState(f io.Writer, state *GameState, args ...string) {
 		p.Fprintf(f, "%s%2d ", indent, r)
 		for c := Coordinate(0); c < w; c++ {
 			t := tiles[r][c]
-			l := ' '
+			l := ""
 			switch t.kind {
 			case TILE_LETTER, TILE_JOKER:
 				if t.letter != 0 {
-					l = unicode.ToUpper(corpus.LetterToRune(t.letter))
+					l = strings.ToUpper(corpus.LetterToString(t.letter))
 				}
 
 			}
-			p.Fprintf(f, "|  %c  ", l)
+			p.Fprintf(f, "|  %-3s", l)
 
 		}
 		p.Fprintf(f, "|\n")
@@ -330,15 +330,15 @@ func FprintStateBoard(f io.Writer, state *GameState, args ...string) {
 		p.Fprintf(f, "%s%2d ", indent, r)
 		for c := Coordinate(0); c < w; c++ {
 			t := tiles[r][c]
-			l := ' '
+			l := ""
 			switch t.kind {
 			case TILE_LETTER, TILE_JOKER:
 				if t.letter != 0 {
-					l = unicode.ToUpper(corpus.LetterToRune(t.letter))
+					l = strings.ToUpper(corpus.LetterToString(t.letter))
 				}
 
 			}
-			p.Fprintf(f, "|  %c  ", l)
+			p.Fprintf(f, "|  %-3s", l)
 
 		}
 		p.Fprintf(f, "|\n")
@@ -0,0 +1,457 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	. "wordfeud/corpus"
+)
+
+// Action is the turn of a person playing a game - the word placed from the position (row and column
+// from 0) in the orientation for a move and the tiles returned to the bag for an exchange
+type Action struct {
+	Kind        MoveKind
+	Row         int
+	Column      int
+	Orientation Orientation
+	Word        string // all the letters of the word - a lower case letter is a joker placed in the move
+	Tiles       string // the tiles to exchange - ? is a joker
+}
+
+// GameView is the state of a game as seen by a player - the racks of the other players are hidden
+type GameView struct {
+	Language     string           `json:"language"`
+	Dictionary   string           `json:"dictionary"`
+	Fingerprint  string           `json:"fingerprint"`
+	Ruleset      string           `json:"ruleset"`
+	Seed         uint64           `json:"seed,omitempty"` // the seed given to NewGame - it reveals the racks and the bag so it is shown when the game has ended
+	Ended        bool             `json:"ended"`
+	NextPlayer   PlayerNo         `json:"nextPlayer"` // 0 when the game has ended
+	BagSize      int              `json:"bagSize"`
+	Board        [][]string       `json:"board"`        // the letter on each square - a joker in lower case - empty when free
+	Squares      [][]string       `json:"squares"`      // DW, TW, DL, TL, CE (center) or empty
+	LetterScores map[string]Score `json:"letterScores"` // the score of each letter - a joker scores 0
+	Players      []PlayerView     `json:"players"`
+	Result       []string         `json:"result,omitempty"` // the result messages when the game has ended
+}
+
+type PlayerView struct {
+	No       PlayerNo `json:"no"`
+	Name     string   `json:"name"`
+	Bot      bool     `json:"bot"`
+	Level    string   `json:"level,omitempty"` // the level of a bot
+	Score    Score    `json:"score"`
+	RackSize int      `json:"rackSize"`
+	Rack     []string `json:"rack,omitempty"` // the tiles of the viewing player only - ? is a joker
+}
+
+type MoveView struct {
+	SeqNo       uint          `json:"seqno"`
+	Player      PlayerNo      `json:"player"`
+	Name        string        `json:"name"`
+	Kind        string        `json:"kind"`
+	Row         int           `json:"row,omitempty"`
+	Column      int           `json:"column,omitempty"`
+	Orientation string        `json:"orientation,omitempty"`
+	Word        string        `json:"word,omitempty"`
+	Exchanged   int           `json:"exchanged,omitempty"` // the number of tiles exchanged
+	Score       Score         `json:"score"`
+	Metadata    *WordMetadata `json:"metadata,omitempty"` // the lemma, part of speech, frequency rank and gloss of the word when known
+}
+
+// MovePreview is the score and the words of a move which has not been played
+type MovePreview struct {
+	Score Score         `json:"score"`
+	Words []WordPreview `json:"words"` // the word of the move followed by the words formed across it
+}
+
+type WordPreview struct {
+	Word     string        `json:"word"`
+	Score    Score         `json:"score"`
+	Metadata *WordMetadata `json:"metadata,omitempty"` // the lemma, part of speech, frequency rank and gloss of the word when known
+}
+
+func ParseMoveKind(kindSpec string) (MoveKind, error) {
+	switch strings.ToLower(kindSpec) {
+	case "move", "place":
+		return MOVE_PLACE, nil
+	case "pass":
+		return MOVE_PASS, nil
+	case "exchange":
+		return MOVE_EXCHANGE, nil
+	case "resign":
+		return MOVE_RESIGN, nil
+	}
+	return MOVE_PLACE, fmt.Errorf("unknown move kind \"%s\" - expected move, exchange, pass or resign", kindSpec)
+}
+
+func (kind MoveKind) String() string {
+	switch kind {
+	case MOVE_PLACE:
+		return "move"
+	case MOVE_PASS:
+		return "pass"
+	case MOVE_EXCHANGE:
+		return "exchange"
+	case MOVE_RESIGN:
+		return "resign"
+	}
+	panic(fmt.Sprintf("invalid MoveKind %d", kind))
+}
+
+func ParseOrientation(orientationSpec string) (Orientation, error) {
+	switch strings.ToLower(orientationSpec) {
+	case "horizontal", "h", "across":
+		return HORIZONTAL, nil
+	case "vertical", "v", "down":
+		return VERTICAL, nil
+	}
+	return HORIZONTAL, fmt.Errorf("unknown orientation \"%s\" - expected horizontal or vertical", orientationSpec)
+}
+
+func (game *_Game) Ended() bool {
+	return game.ended
+}
+
+// NextPlayer returns the player to move - NoPlayer when the game has ended
+func (game *_Game) NextPlayer() PlayerNo {
+	if game.ended {
+		return NoPlayer
+	}
+	return game.state.NextPlayer()
+}
+
+// PlayBots plays the moves of the bot players until it is the turn of a human player or the game has ended
+func (game *_Game) PlayBots() {
+	for !game.ended && !game.players[game.state.NextPlayer()].human {
+		if !game.playBot() {
+			return
+		}
+	}
+}
+
+// playBot plays the turn of a bot - unlike Play a bot without a possible move passes, so the
+// game goes on for the human players
+func (game *_Game) playBot() bool {
+	if game.ended {
+		return false
+	}
+	state, playerState := game.nextState()
+	if state.Move(playerState) == nil && state.move == nil {
+		state.AddPass(playerState)
+	}
+	return game.completeMove(state, playerState)
+}
+
+// PlayAction plays the turn of player playerNo - the state of game is unchanged when an error is returned
+func (game *_Game) PlayAction(playerNo PlayerNo, action Action) error {
+	if err := game.checkTurn(playerNo); err != nil {
+		return err
+	}
+	state, playerState := game.nextState()
+	switch action.Kind {
+	case MOVE_PLACE:
+		partial, err := state.findMove(playerState, action)
+		if err != nil {
+			return err
+		}
+		state.AddMove(partial, playerState)
+	case MOVE_PASS:
+		state.AddPass(playerState)
+	case MOVE_EXCHANGE:
+		if err := state.AddExchange(playerState, action.Tiles); err != nil {
+			return err
+		}
+	case MOVE_RESIGN:
+		state.AddPass(playerState).kind = MOVE_RESIGN
+		game.resigned = playerNo
+	default:
+		panic(fmt.Sprintf("invalid MoveKind %d (PlayAction)", action.Kind))
+	}
+	game.completeMove(state, playerState)
+	return nil
+}
+
+// PreviewAction returns the score and the words of the move of action by player playerNo without playing it
+func (game *_Game) PreviewAction(playerNo PlayerNo, action Action) (*MovePreview, error) {
+	if err := game.checkTurn(playerNo); err != nil {
+		return nil, err
+	}
+	state, playerState := game.nextState()
+	partial, err := state.findMove(playerState, action)
+	if err != nil {
+		return nil, err
+	}
+	preview := &MovePreview{Score: partial.score.score, Words: make([]WordPreview, 0, len(partial.score.wordScores))}
+	for _, wordScore := range partial.score.wordScores {
+		tiles := make(Tiles, len(wordScore.tileScores))
+		for i, tileScore := range wordScore.tileScores {
+			tiles[i] = tileScore.tile.Tile
+		}
+		preview.Words = append(preview.Words, WordPreview{Word: state.TilesToString(tiles), Score: wordScore.score, Metadata: game.wordMetadata(tiles)})
+	}
+	return preview, nil
+}
+
+// wordMetadata returns the metadata of the word of tiles in the content of the game - nil when it has none
+func (game *_Game) wordMetadata(tiles Tiles) *WordMetadata {
+	metadata, found := game.content.Metadata(game.TilesToWord(tiles))
+	if !found {
+		return nil
+	}
+	return &metadata
+}
+
+func (game *_Game) checkTurn(playerNo PlayerNo) error {
+	if game.ended {
+		return fmt.Errorf("the game has ended")
+	}
+	if next := game.state.NextPlayer(); playerNo != next {
+		return fmt.Errorf("it is the turn of player %d - not of player %d", next, playerNo)
+	}
+	return nil
+}
+
+// findMove returns the move of action among the moves which can be made from the rack of playerState
+// - the best scoring when jokers can be placed in more than one way
+func (state *GameState) findMove(playerState *PlayerState, action Action) (*PartialMove, error) {
+	game := state.game
+	pos := Position{Coordinate(action.Row), Coordinate(action.Column)}
+	if action.Row < 0 || action.Column < 0 || action.Row > 255 || action.Column > 255 || !game.IsValidPos(pos) {
+		return nil, fmt.Errorf("position (%d,%d) is not on the board", action.Row, action.Column)
+	}
+	letters, jokers, err := parseActionWord(game.corpus, action.Word)
+	if err != nil {
+		return nil, err
+	}
+	state.PrepareMove()
+	var found *PartialMove
+	for _, move := range state.GenerateAllMoves(playerState) {
+		if move.direction.Orientation() != action.Orientation || len(move.tiles) != len(letters) {
+			continue
+		}
+		tiles := move.tiles.inBoardOrder()
+		if tiles[0].pos != pos {
+			continue
+		}
+		matches := true
+		for i, tile := range tiles {
+			if tile.letter != letters[i] || (tile.placedInMove && (tile.kind == TILE_JOKER) != jokers[i]) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+		if move.score == nil {
+			move.score = state.CalcScore(move.tiles, move.direction.Orientation())
+		}
+		if found == nil || move.score.score > found.score.score {
+			found = move
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("\"%s\" %s at (%d,%d) is not a valid move with the rack %s",
+			action.Word, action.Orientation.String(), action.Row, action.Column, playerState.rack.Pretty(game.corpus))
+	}
+	return found, nil
+}
+
+// parseActionWord returns the letters of word and whether each of them is in lower case (a joker)
+func parseActionWord(corpus Corpus, word string) ([]Letter, []bool, error) {
+	letters := make([]Letter, 0, len(word))
+	jokers := make([]bool, 0, len(word))
+	upper := strings.ToUpper(word)
+	if len(upper) != len(word) {
+		return nil, nil, fmt.Errorf("\"%s\" cannot be parsed as a word of the %s alphabet", word, corpus.Language().String())
+	}
+	for i := 0; i < len(word); {
+		letter, n := corpus.ParseLetter(upper[i:])
+		if letter == NoLetter {
+			r, _ := utf8.DecodeRuneInString(word[i:])
+			return nil, nil, fmt.Errorf("'%c' in \"%s\" is not a letter of the %s alphabet", r, word, corpus.Language().String())
+		}
+		r, _ := utf8.DecodeRuneInString(word[i:])
+		letters = append(letters, letter)
+		jokers = append(jokers, unicode.IsLower(r))
+		i += n
+	}
+	if len(letters) < 2 {
+		return nil, nil, fmt.Errorf("\"%s\" is not a word of at least 2 letters", word)
+	}
+	return letters, jokers, nil
+}
+
+// AddExchange returns the tiles of tilesSpec from the rack of playerState to the bag after drawing as many new tiles
+func (state *GameState) AddExchange(playerState *PlayerState, tilesSpec string) error {
+	game := state.game
+	exchanged, err := ParseRack(game.corpus, tilesSpec)
+	if err != nil {
+		return err
+	}
+	if len(exchanged) == 0 {
+		return fmt.Errorf("no tiles to exchange")
+	}
+	if len(state.freeTiles) < RackSize {
+		return fmt.Errorf("tiles can only be exchanged while the bag holds at least %d tiles - it holds %d", RackSize, len(state.freeTiles))
+	}
+	rack := slices.Clone(playerState.rack)
+	for _, tile := range exchanged {
+		i := slices.IndexFunc(rack, func(t Tile) bool {
+			return t.kind == tile.kind && (t.kind == TILE_JOKER || t.letter == tile.letter)
+		})
+		if i < 0 {
+			return fmt.Errorf("the rack %s does not hold the tiles \"%s\"", playerState.rack.Pretty(game.corpus), tilesSpec)
+		}
+		rack = slices.Delete(rack, i, i+1)
+	}
+	playerState.rack = rack
+	state.FillRack(playerState)
+	for _, tile := range exchanged {
+		if tile.kind == TILE_JOKER {
+			tile.letter = 0
+		}
+		state.freeTiles = append(state.freeTiles, tile)
+	}
+	move := state.AddPass(playerState)
+	move.kind = MOVE_EXCHANGE
+	move.exchanged = len(exchanged)
+	return nil
+}
+
+// View returns the state of game as seen by player playerNo - NoPlayer sees no racks
+func (game *_Game) View(playerNo PlayerNo) *GameView {
+	state := game.state
+	corpus := game.corpus
+	header := game.Header()
+	view := &GameView{
+		Language:     header.Language,
+		Dictionary:   header.Dictionary,
+		Fingerprint:  header.Fingerprint,
+		Ruleset:      header.Ruleset,
+		Ended:        game.ended,
+		NextPlayer:   game.NextPlayer(),
+		BagSize:      len(state.freeTiles),
+		Board:        make([][]string, game.dimensions.Height),
+		Squares:      make([][]string, game.dimensions.Height),
+		Players:      make([]PlayerView, 0, len(state.playerStates)-1),
+		LetterScores: make(map[string]Score),
+	}
+	if game.ended {
+		view.Seed = game.options.RandSeed
+	}
+	for _, tile := range GetLanguageTiles(corpus.Language()) {
+		view.LetterScores[tile.Character()] = Score(tile.Value())
+	}
+	for r := range view.Board {
+		view.Board[r] = make([]string, game.dimensions.Width)
+		view.Squares[r] = make([]string, game.dimensions.Width)
+		for c := range view.Board[r] {
+			tile := state.tileBoard[r][c]
+			switch tile.kind {
+			case TILE_LETTER:
+				view.Board[r][c] = corpus.LetterToString(tile.letter)
+			case TILE_JOKER:
+				view.Board[r][c] = strings.ToLower(corpus.LetterToString(tile.letter))
+			}
+			view.Squares[r][c] = game.board.squares[r][c].Name()
+		}
+	}
+	for _, ps := range state.playerStates {
+		if ps.playerNo == NoPlayer {
+			continue
+		}
+		player := PlayerView{
+			No:       ps.playerNo,
+			Name:     ps.player.name,
+			Bot:      !ps.player.human,
+			Score:    ps.score,
+			RackSize: len(ps.rack),
+		}
+		if !ps.player.human {
+			player.Level = ps.player.level.String()
+		}
+		if ps.playerNo == playerNo {
+			player.Rack = make([]string, len(ps.rack))
+			for i, tile := range ps.rack {
+				if tile.kind == TILE_JOKER {
+					player.Rack[i] = string(JokerRune)
+				} else {
+					player.Rack[i] = corpus.LetterToString(tile.letter)
+				}
+			}
+		}
+		view.Players = append(view.Players, player)
+	}
+	if game.ended {
+		for _, category := range AllMessageCategories {
+			view.Result = append(view.Result, game.messages[category]...)
+		}
+	}
+	return view
+}
+
+// Moves returns the moves of game in the order they were made
+func (game *_Game) Moves() []MoveView {
+	moves := make([]MoveView, 0)
+	for _, state := range game.CollectStates() {
+		move := state.move
+		if move == nil {
+			continue
+		}
+		view := MoveView{
+			SeqNo:     move.seqno,
+			Player:    move.playerState.playerNo,
+			Name:      move.playerState.player.name,
+			Kind:      move.kind.String(),
+			Exchanged: move.exchanged,
+			Score:     move.score.score,
+		}
+		if move.kind == MOVE_PLACE && len(move.tiles) > 0 {
+			first := move.tiles.inBoardOrder()[0].pos
+			view.Row = int(first.row)
+			view.Column = int(first.column)
+			view.Orientation = move.direction.Orientation().String()
+			view.Word = state.TilesToString(move.tiles.Tiles())
+			if len(move.score.wordScores) > 0 {
+				tiles := make(Tiles, len(move.score.wordScores[0].tileScores))
+				for i, tileScore := range move.score.wordScores[0].tileScores {
+					tiles[i] = tileScore.tile.Tile
+				}
+				view.Metadata = game.wordMetadata(tiles)
+			}
+		}
+		moves = append(moves, view)
+	}
+	return moves
+}
+
+// inBoardOrder returns the tiles ordered by row and column - the first is the start of the word
+func (tiles MoveTiles) inBoardOrder() MoveTiles {
+	ordered := slices.Clone(tiles)
+	slices.SortFunc(ordered, func(a, b MoveTile) int {
+		return cmp.Or(cmp.Compare(a.pos.row, b.pos.row), cmp.Compare(a.pos.column, b.pos.column))
+	})
+	return ordered
+}
+
+// Name returns the abbreviation of the square - empty for a plain square
+func (square Square) Name() string {
+	switch square {
+	case DW:
+		return "DW"
+	case TW:
+		return "TW"
+	case DL:
+		return "DL"
+	case TL:
+		return "TL"
+	case CE:
+		return "CE"
+	}
+	return ""
+}
@@ -0,0 +1,221 @@
+package game
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+
+	"golang.org/x/text/language"
+)
+
+func Test_PlayAction(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_PlayAction() failed to create corpus : %v", err)
+		return
+	}
+	content, err := newTestContent(corpus, "../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_PlayAction() failed to create corpus content : %v", err)
+		return
+	}
+	options := &GameOptions{
+		Language: language.Danish,
+		RandSeed: 1,
+		Rand:     rand.New(rand.NewSource(1)),
+		Count:    1,
+		Out:      io.Discard,
+	}
+	g, err := newGame(options, 1, Players{HumanPlayer("ann"), BotPlayer(1)}, content)
+	if err != nil {
+		t.Errorf("Test_PlayAction() failed to create game : %v", err)
+		return
+	}
+	game := g._Game()
+
+	game.PlayBots()
+	if game.NextPlayer() != 1 || len(game.Moves()) != 0 {
+		t.Errorf("Test_PlayAction() the bots played before the human player")
+	}
+	if err := game.PlayAction(2, Action{Kind: MOVE_PASS}); err == nil {
+		t.Errorf("Test_PlayAction() player 2 played the turn of player 1")
+	}
+
+	view := game.View(1)
+	if view.Seed != 0 {
+		t.Errorf("Test_PlayAction() the view of a running game shows the seed %d", view.Seed)
+	}
+	if len(view.Players[0].Rack) != RackSize || len(view.Players[1].Rack) != 0 {
+		t.Errorf("Test_PlayAction() player 1 sees racks of %d and %d tiles", len(view.Players[0].Rack), len(view.Players[1].Rack))
+	}
+	if err := game.PlayAction(1, Action{Kind: MOVE_EXCHANGE, Tiles: view.Players[0].Rack[0]}); err != nil {
+		t.Errorf("Test_PlayAction() exchange failed : %v", err)
+	}
+	if after := game.View(1); after.BagSize != view.BagSize || len(after.Players[0].Rack) != RackSize {
+		t.Errorf("Test_PlayAction() the exchange left %d tiles in the bag and %d on the rack", after.BagSize, len(after.Players[0].Rack))
+	}
+	game.PlayBots()
+	if game.NextPlayer() != 1 {
+		t.Errorf("Test_PlayAction() the bot did not play")
+	}
+
+	moves := len(game.Moves())
+	if err := game.PlayAction(1, Action{Kind: MOVE_PLACE, Row: 0, Column: 0, Word: "ØØØ"}); err == nil {
+		t.Errorf("Test_PlayAction() an invalid move was played")
+	}
+	if len(game.Moves()) != moves {
+		t.Errorf("Test_PlayAction() an invalid move changed the game")
+	}
+	action, best := bestAction(game)
+	if best == nil {
+		t.Errorf("Test_PlayAction() player 1 has no moves")
+		return
+	}
+	preview, err := game.PreviewAction(1, action)
+	if err != nil || preview.Score != best.score.score || len(preview.Words) == 0 {
+		t.Errorf("Test_PlayAction() preview of %+v is %+v : %v - expected a score of %d", action, preview, err, best.score.score)
+	}
+	if len(game.Moves()) != moves {
+		t.Errorf("Test_PlayAction() the preview changed the game")
+	}
+	if err := game.PlayAction(1, action); err != nil {
+		t.Errorf("Test_PlayAction() move %+v failed : %v", action, err)
+		return
+	}
+	played := game.Moves()[moves]
+	if played.Kind != "move" || played.Score != best.score.score || played.Row != action.Row || played.Column != action.Column {
+		t.Errorf("Test_PlayAction() played %+v - expected %+v scoring %d", played, action, best.score.score)
+	}
+
+	// a bot without tiles has no move - Play stops the game while PlayBots passes
+	game.state.playerStates[2].rack = Rack{}
+	moves = len(game.Moves())
+	if game.Play() || len(game.Moves()) != moves {
+		t.Errorf("Test_PlayAction() Play played a bot without a possible move")
+	}
+	game.PlayBots()
+	if len(game.Moves()) != moves+1 || game.Moves()[moves].Kind != "pass" || game.NextPlayer() != 1 {
+		t.Errorf("Test_PlayAction() the bot without a possible move did not pass")
+	}
+	if err := game.PlayAction(1, Action{Kind: MOVE_RESIGN}); err != nil {
+		t.Errorf("Test_PlayAction() resign failed : %v", err)
+	}
+	if !game.Ended() || game.NextPlayer() != NoPlayer {
+		t.Errorf("Test_PlayAction() the game did not end when player 1 resigned")
+	}
+	if view := game.View(NoPlayer); view.Seed != options.RandSeed {
+		t.Errorf("Test_PlayAction() the view of the ended game has the seed %d and not the seed %d of the game", view.Seed, options.RandSeed)
+	}
+	if err := game.PlayAction(1, Action{Kind: MOVE_PASS}); err == nil {
+		t.Errorf("Test_PlayAction() a move was played after the game ended")
+	}
+}
+
+// bestAction returns the action of the best move of the next player - the move is nil when there is none
+func bestAction(game *_Game) (Action, *PartialMove) {
+	allMoves, state := nextMoves(game)
+	var best *PartialMove
+	for _, move := range allMoves {
+		if move.score == nil {
+			move.score = state.CalcScore(move.tiles, move.direction.Orientation())
+		}
+		if best == nil || move.score.score > best.score.score {
+			best = move
+		}
+	}
+	if best == nil {
+		return Action{}, nil
+	}
+	tiles := best.tiles.inBoardOrder()
+	var word strings.Builder
+	for _, tile := range tiles {
+		letter := game.corpus.LetterToString(tile.letter)
+		if tile.placedInMove && tile.kind == TILE_JOKER {
+			letter = strings.ToLower(letter)
+		}
+		word.WriteString(letter)
+	}
+	action := Action{
+		Kind:        MOVE_PLACE,
+		Row:         int(tiles[0].pos.row),
+		Column:      int(tiles[0].pos.column),
+		Orientation: best.direction.Orientation(),
+		Word:        word.String(),
+	}
+	return action, best
+}
+
+func Test_PreviewMetadata(t *testing.T) {
+	corpus, err := NewCorpus(language.Danish)
+	if err != nil {
+		t.Errorf("Test_PreviewMetadata() failed to create corpus : %v", err)
+		return
+	}
+	partial, err := newTestContent(corpus, "../data_test/dk_partial.txt")
+	if err != nil {
+		t.Errorf("Test_PreviewMetadata() failed to create corpus content : %v", err)
+		return
+	}
+	words := make([]string, 0)
+	metadata := make(Metadata)
+	for i, word := range partial.Words() {
+		w := word.String(corpus)
+		words = append(words, w)
+		metadata[w] = WordMetadata{Lemma: strings.ToLower(w), POS: "sb.", FrequencyRank: i + 1, Gloss: "gloss of " + w}
+	}
+	fileName := path.Join(t.TempDir(), "corpus_da.txt")
+	if err := os.WriteFile(fileName, []byte(strings.Join(words, "\n")+"\n"), 0644); err != nil {
+		t.Errorf("Test_PreviewMetadata() failed to write corpus : %v", err)
+		return
+	}
+	if err := WriteMetadataFile(MetadataFileName(fileName), metadata); err != nil {
+		t.Errorf("Test_PreviewMetadata() failed to write metadata : %v", err)
+		return
+	}
+	content, err := corpus.GetFileContent(fileName)
+	if err != nil {
+		t.Errorf("Test_PreviewMetadata() failed to read corpus content : %v", err)
+		return
+	}
+	options := &GameOptions{
+		Language: language.Danish,
+		RandSeed: 1,
+		Rand:     rand.New(rand.NewSource(1)),
+		Count:    1,
+		Out:      io.Discard,
+	}
+	g, err := newGame(options, 1, Players{HumanPlayer("ann"), BotPlayer(1)}, content)
+	if err != nil {
+		t.Errorf("Test_PreviewMetadata() failed to create game : %v", err)
+		return
+	}
+	game := g._Game()
+
+	action, best := bestAction(game)
+	if best == nil {
+		t.Errorf("Test_PreviewMetadata() player 1 has no moves")
+		return
+	}
+	preview, err := game.PreviewAction(1, action)
+	if err != nil || len(preview.Words) == 0 {
+		t.Errorf("Test_PreviewMetadata() preview of %+v is %+v : %v", action, preview, err)
+		return
+	}
+	for _, word := range preview.Words {
+		if word.Metadata == nil || *word.Metadata != metadata[word.Word] {
+			t.Errorf("Test_PreviewMetadata() the metadata of %s is %+v - expected %+v", word.Word, word.Metadata, metadata[word.Word])
+		}
+	}
+	if err := game.PlayAction(1, action); err != nil {
+		t.Errorf("Test_PreviewMetadata() move %+v failed : %v", action, err)
+		return
+	}
+	if played := game.Moves()[0]; played.Metadata == nil || *played.Metadata != metadata[preview.Words[0].Word] {
+		t.Errorf("Test_PreviewMetadata() the metadata of the move %s is %+v", played.Word, played.Metadata)
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	. "wordfeud/context"
 	. "wordfeud/game"
+
+	"golang.org/x/text/language"
 )
 
 func gameCmd(options *GameOptions, args []string) *GameResult {
+	if len(args) > 0 && args[0] == "check" {
+		return gameCheckCmd(options, args[1:])
+	}
 	result := new(GameResult)
 
 	flag := flag.NewFlagSet("exit", flag.ExitOnError)
@@ -15,7 +20,7 @@ func gameCmd(options *GameOptions, args []string) *GameResult {
 
 	flag.Parse(args)
 
-	game, err := NewGame(options, 1, Players{BotPlayer(1), BotPlayer(2)})
+	game, err := NewGame(options, 1, BotPlayers(options, 2))
 	if err != nil {
 		fmt.Println(result.errors(), err.Error())
 		return result.result()
@@ -31,3 +36,50 @@ func gameCmd(options *GameOptions, args []string) *GameResult {
 
 	return result.result()
 }
+
+// gameCheckCmd compares the dictionary fingerprint and the ruleset of the game files with the current
+// dictionary of the game - a mismatch is a warning or with -strict an error
+func gameCheckCmd(options *GameOptions, args []string) *GameResult {
+	result := new(GameResult)
+	var strict bool
+	flag := flag.NewFlagSet("game check", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	BoolVarFlag(flag, &strict, []string{"strict"}, false, "refuse game files played with another dictionary")
+	flag.Parse(args)
+	if flag.NArg() == 0 {
+		fmt.Fprintln(result.errors(), "Please specify the game files to check")
+		return result.result()
+	}
+	for _, fileName := range flag.Args() {
+		header, err := ReadGameHeader(fileName)
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			continue
+		}
+		result.Headers = append(result.Headers, header)
+		gameOptions := options.Copy()
+		gameOptions.Dictionary = header.Dictionary
+		if header.Language != "" {
+			if gameOptions.Language, err = language.Parse(header.Language); err != nil {
+				fmt.Fprintf(result.errors(), "game file \"%s\" : %s\n", fileName, err.Error())
+				continue
+			}
+		}
+		game, err := NewGame(gameOptions, 1, BotPlayers(gameOptions, 2))
+		if err != nil {
+			fmt.Fprintf(result.errors(), "game file \"%s\" : %s\n", fileName, err.Error())
+			continue
+		}
+		if err := header.Check(game); err != nil {
+			if strict {
+				fmt.Fprintf(result.errors(), "game file \"%s\" refused : %s\n", fileName, err.Error())
+			} else {
+				fmt.Fprintf(result.logger(), "warning: game file \"%s\" : %s\n", fileName, err.Error())
+			}
+			continue
+		}
+		fmt.Fprintf(result.logger(), "game file \"%s\" : dictionary fingerprint %s ruleset %s match\n",
+			fileName, header.Fingerprint, header.Ruleset)
+	}
+	return result.result()
+}
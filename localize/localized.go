@@ -4,16 +4,29 @@ import (
 	"golang.org/x/text/language"
 )
 
+var NorwegianBokmal = language.MustParse("nb")
+
+// Localized translates the (English) text to lang - texts in English and unknown texts are returned as is
 func Localized(lang language.Tag, text string) string {
 	switch lang {
 	case language.Danish:
 		return danish(text)
+	case language.Swedish:
+		return swedish(text)
+	case NorwegianBokmal:
+		return norwegian(text)
 	}
 	return text
 }
 
 func danish(text string) string {
 	switch text {
+	case `"%s" is a form of "%s"`:
+		return `"%s" er en bøjning af "%s"`
+	case `frequency rank %d`:
+		return `hyppighed nummer %d`
+	case `Game completed after %d moves as %s resigned`:
+		return `Spillet afsluttet efter %d træk da %s opgav`
 	case `Game completed after %d moves as %s has no more tiles in rack`:
 		return `Spillet afsluttet efter %d træk da %s ikke har flere brikker`
 	case `Game completed after %d moves as there has been %d conequtive passes`:
@@ -76,6 +89,406 @@ func danish(text string) string {
 		return `Spil èt spil`
 	case `Top level menu`:
 		return `Hoved menu`
+	case `Level of %s`:
+		return `Niveau for %s`
+	case `Dictionary`:
+		return `Ordbog`
+	case `ruleset`:
+		return `regelsæt`
+	case `beginner`:
+		return `begynder`
+	case `casual`:
+		return `øvet`
+	case `strong`:
+		return `stærk`
+	case `expert`:
+		return `ekspert`
+	case `Sign in`:
+		return `Log ind`
+	case `Sign out`:
+		return `Log ud`
+	case `Create user`:
+		return `Opret bruger`
+	case `User name`:
+		return `Brugernavn`
+	case `Password`:
+		return `Adgangskode`
+	case `Mail (optional)`:
+		return `Mail (valgfri)`
+	case `Users cannot sign in as the user store is not available`:
+		return `Brugere kan ikke logge ind da brugerdatabasen ikke er tilgængelig`
+	case `Play against a robot`:
+		return `Spil mod en robot`
+	case `New game`:
+		return `Nyt spil`
+	case `Robot level`:
+		return `Robottens niveau`
+	case `Your games`:
+		return `Dine spil`
+	case `You have no game %s`:
+		return `Du har intet spil %s`
+	case `Moves`:
+		return `Træk`
+	case `Your turn`:
+		return `Din tur`
+	case `The robot is playing`:
+		return `Robotten spiller`
+	case `Game over`:
+		return `Spillet er slut`
+	case `Bag`:
+		return `Posen`
+	case `tiles`:
+		return `brikker`
+	case `Play`:
+		return `Spil`
+	case `Recall`:
+		return `Tag tilbage`
+	case `Shuffle`:
+		return `Bland`
+	case `Exchange`:
+		return `Byt`
+	case `Pass`:
+		return `Pas`
+	case `Resign`:
+		return `Opgiv`
+	case `Cancel`:
+		return `Annuller`
+	case `Choose the letter of the blank`:
+		return `Vælg bogstavet for den blanke brik`
+	case `Select the tiles to exchange`:
+		return `Vælg de brikker der skal byttes`
+	case `Do you want to resign?`:
+		return `Vil du opgive spillet?`
+	case `Place tiles on the board`:
+		return `Læg brikker på brættet`
+	case `The tiles must be placed in one row or column`:
+		return `Brikkerne skal lægges i én række eller søjle`
+	case `The placed tiles must form one word`:
+		return `De lagte brikker skal danne ét ord`
+	case `%s gives %d points`:
+		return `%s giver %d point`
+	case `passed`:
+		return `meldte pas`
+	case `exchanged %d tiles`:
+		return `byttede %d brikker`
+	case `resigned`:
+		return `opgav`
+	}
+	return text
+}
+
+func swedish(text string) string {
+	switch text {
+	case `"%s" is a form of "%s"`:
+		return `"%s" är en böjningsform av "%s"`
+	case `frequency rank %d`:
+		return `frekvens nummer %d`
+	case `Game completed after %d moves as %s resigned`:
+		return `Spelet avslutat efter %d drag då %s gav upp`
+	case `Game completed after %d moves as %s has no more tiles in rack`:
+		return `Spelet avslutat efter %d drag då %s inte har fler brickor`
+	case `Game completed after %d moves as there has been %d conequtive passes`:
+		return `Spelet avslutat efter %d drag då det har varit %d pass i rad`
+	case `Game is a draw between %d players: %s`:
+		return `Spelet är oavgjort mellan %d spelare: %s`
+	case `Game is won by %s`:
+		return `Spelet vanns av %s`
+	case `Wrote game file after move %d "%s"`:
+		return `Skrev spelfil efter drag nummer %d "%s"`
+	case `Game file is %s`:
+		return `Spelfilen är %s`
+	case `%s scored %d and has %s left`:
+		return `%s fick %d poäng och har %s kvar`
+	case `Scrabble game`:
+		return `Scrabble-spel`
+	case `Random number generator seed:`:
+		return `Slumptalsgeneratorns frö:`
+	case `Remaining free tiles:`:
+		return `Återstående lediga brickor:`
+	case `Number of moves in game:`:
+		return `Antal drag i spelet:`
+	case `%d tiles`:
+		return `%d brickor`
+	case `1 tile`:
+		return `1 bricka`
+	case `no tiles`:
+		return `inga brickor`
+	case `%s move number %d %s %s..%s "%s" gives score %d`:
+		return `%s drag nummer %d %s %s..%s "%s" ger %d poäng`
+	case `%s has total score %d and %s`:
+		return `%s har %d poäng och %s`
+	case `initial board`:
+		return `spelplanen`
+	case `Initial board`:
+		return `Spelplanen`
+	case `%s played "%s" %s at %s scoring %d`:
+		return `%s spelade "%s" %s från %s för %d poäng`
+	case `Move number %d`:
+		return `Drag nummer %d`
+	case `%s played "%s" %s at %s giving %d points`:
+		return `%s spelade "%s" %s från %s för %d poäng`
+	case `game overview`:
+		return `spelöversikten`
+	case `previous move`:
+		return `föregående drag`
+	case `next move`:
+		return `nästa drag`
+	case `%d points`:
+		return `%d poäng`
+	case `vertical`:
+		return `vertikalt`
+	case `horizontal`:
+		return `horisontellt`
+	case `Scrabble`:
+		return `Scrabble`
+	case `Two robot player game`:
+		return `Spel med två robotspelare`
+	case `Play game`:
+		return `Spela ett spel`
+	case `Top level menu`:
+		return `Huvudmeny`
+	case `Level of %s`:
+		return `Nivå för %s`
+	case `Dictionary`:
+		return `Ordlista`
+	case `ruleset`:
+		return `regeluppsättning`
+	case `beginner`:
+		return `nybörjare`
+	case `casual`:
+		return `van`
+	case `strong`:
+		return `stark`
+	case `expert`:
+		return `expert`
+	case `Sign in`:
+		return `Logga in`
+	case `Sign out`:
+		return `Logga ut`
+	case `Create user`:
+		return `Skapa användare`
+	case `User name`:
+		return `Användarnamn`
+	case `Password`:
+		return `Lösenord`
+	case `Mail (optional)`:
+		return `E-post (valfri)`
+	case `Users cannot sign in as the user store is not available`:
+		return `Användare kan inte logga in då användardatabasen inte är tillgänglig`
+	case `Play against a robot`:
+		return `Spela mot en robot`
+	case `New game`:
+		return `Nytt spel`
+	case `Robot level`:
+		return `Robotens nivå`
+	case `Your games`:
+		return `Dina spel`
+	case `You have no game %s`:
+		return `Du har inget spel %s`
+	case `Moves`:
+		return `Drag`
+	case `Your turn`:
+		return `Din tur`
+	case `The robot is playing`:
+		return `Roboten spelar`
+	case `Game over`:
+		return `Spelet är slut`
+	case `Bag`:
+		return `Påsen`
+	case `tiles`:
+		return `brickor`
+	case `Play`:
+		return `Spela`
+	case `Recall`:
+		return `Ta tillbaka`
+	case `Shuffle`:
+		return `Blanda`
+	case `Exchange`:
+		return `Byt`
+	case `Pass`:
+		return `Passa`
+	case `Resign`:
+		return `Ge upp`
+	case `Cancel`:
+		return `Avbryt`
+	case `Choose the letter of the blank`:
+		return `Välj bokstaven för den blanka brickan`
+	case `Select the tiles to exchange`:
+		return `Välj brickorna som ska bytas`
+	case `Do you want to resign?`:
+		return `Vill du ge upp spelet?`
+	case `Place tiles on the board`:
+		return `Lägg brickor på brädet`
+	case `The tiles must be placed in one row or column`:
+		return `Brickorna måste läggas i en rad eller kolumn`
+	case `The placed tiles must form one word`:
+		return `De lagda brickorna måste bilda ett ord`
+	case `%s gives %d points`:
+		return `%s ger %d poäng`
+	case `passed`:
+		return `passade`
+	case `exchanged %d tiles`:
+		return `bytte %d brickor`
+	case `resigned`:
+		return `gav upp`
+	}
+	return text
+}
+
+func norwegian(text string) string {
+	switch text {
+	case `"%s" is a form of "%s"`:
+		return `"%s" er en bøyningsform av "%s"`
+	case `frequency rank %d`:
+		return `frekvens nummer %d`
+	case `Game completed after %d moves as %s resigned`:
+		return `Spillet avsluttet etter %d trekk da %s ga opp`
+	case `Game completed after %d moves as %s has no more tiles in rack`:
+		return `Spillet avsluttet etter %d trekk da %s ikke har flere brikker`
+	case `Game completed after %d moves as there has been %d conequtive passes`:
+		return `Spillet avsluttet etter %d trekk da det har vært %d pass på rad`
+	case `Game is a draw between %d players: %s`:
+		return `Spillet er uavgjort mellom %d spillere: %s`
+	case `Game is won by %s`:
+		return `Spillet er vunnet av %s`
+	case `Wrote game file after move %d "%s"`:
+		return `Skrev spillfil etter trekk nummer %d "%s"`
+	case `Game file is %s`:
+		return `Spillfilen er %s`
+	case `%s scored %d and has %s left`:
+		return `%s fikk %d poeng og har %s igjen`
+	case `Scrabble game`:
+		return `Scrabble-spill`
+	case `Random number generator seed:`:
+		return `Frø for tilfeldige tall:`
+	case `Remaining free tiles:`:
+		return `Gjenværende ledige brikker:`
+	case `Number of moves in game:`:
+		return `Antall trekk i spillet:`
+	case `%d tiles`:
+		return `%d brikker`
+	case `1 tile`:
+		return `1 brikke`
+	case `no tiles`:
+		return `ingen brikker`
+	case `%s move number %d %s %s..%s "%s" gives score %d`:
+		return `%s trekk nummer %d %s %s..%s "%s" som gir %d poeng`
+	case `%s has total score %d and %s`:
+		return `%s har %d poeng og %s`
+	case `initial board`:
+		return `spillebrettet`
+	case `Initial board`:
+		return `Spillebrettet`
+	case `%s played "%s" %s at %s scoring %d`:
+		return `%s spilte "%s" %s fra %s for %d poeng`
+	case `Move number %d`:
+		return `Trekk nummer %d`
+	case `%s played "%s" %s at %s giving %d points`:
+		return `%s spilte "%s" %s fra %s for %d poeng`
+	case `game overview`:
+		return `spilloversikten`
+	case `previous move`:
+		return `forrige trekk`
+	case `next move`:
+		return `neste trekk`
+	case `%d points`:
+		return `%d poeng`
+	case `vertical`:
+		return `vertikalt`
+	case `horizontal`:
+		return `horisontalt`
+	case `Scrabble`:
+		return `Scrabble`
+	case `Two robot player game`:
+		return `Spill med to robotspillere`
+	case `Play game`:
+		return `Spill et spill`
+	case `Top level menu`:
+		return `Hovedmeny`
+	case `Level of %s`:
+		return `Nivå for %s`
+	case `Dictionary`:
+		return `Ordliste`
+	case `ruleset`:
+		return `regelsett`
+	case `beginner`:
+		return `nybegynner`
+	case `casual`:
+		return `øvet`
+	case `strong`:
+		return `sterk`
+	case `expert`:
+		return `ekspert`
+	case `Sign in`:
+		return `Logg inn`
+	case `Sign out`:
+		return `Logg ut`
+	case `Create user`:
+		return `Opprett bruker`
+	case `User name`:
+		return `Brukernavn`
+	case `Password`:
+		return `Passord`
+	case `Mail (optional)`:
+		return `E-post (valgfri)`
+	case `Users cannot sign in as the user store is not available`:
+		return `Brukere kan ikke logge inn da brukerdatabasen ikke er tilgjengelig`
+	case `Play against a robot`:
+		return `Spill mot en robot`
+	case `New game`:
+		return `Nytt spill`
+	case `Robot level`:
+		return `Robotens nivå`
+	case `Your games`:
+		return `Dine spill`
+	case `You have no game %s`:
+		return `Du har ikke noe spill %s`
+	case `Moves`:
+		return `Trekk`
+	case `Your turn`:
+		return `Din tur`
+	case `The robot is playing`:
+		return `Roboten spiller`
+	case `Game over`:
+		return `Spillet er slutt`
+	case `Bag`:
+		return `Posen`
+	case `tiles`:
+		return `brikker`
+	case `Play`:
+		return `Spill`
+	case `Recall`:
+		return `Ta tilbake`
+	case `Shuffle`:
+		return `Stokk`
+	case `Exchange`:
+		return `Bytt`
+	case `Pass`:
+		return `Pass`
+	case `Resign`:
+		return `Gi opp`
+	case `Cancel`:
+		return `Avbryt`
+	case `Choose the letter of the blank`:
+		return `Velg bokstaven for den blanke brikken`
+	case `Select the tiles to exchange`:
+		return `Velg brikkene som skal byttes`
+	case `Do you want to resign?`:
+		return `Vil du gi opp spillet?`
+	case `Place tiles on the board`:
+		return `Legg brikker på brettet`
+	case `The tiles must be placed in one row or column`:
+		return `Brikkene må legges i én rad eller kolonne`
+	case `The placed tiles must form one word`:
+		return `De lagte brikkene må danne ett ord`
+	case `%s gives %d points`:
+		return `%s gir %d poeng`
+	case `passed`:
+		return `meldte pass`
+	case `exchanged %d tiles`:
+		return `byttet %d brikker`
+	case `resigned`:
+		return `ga opp`
 	}
 	return text
 }
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	. "wordfeud/context"
+	. "wordfeud/game"
+	. "wordfeud/localize"
+	. "wordfeud/store"
+)
+
+// the texts of the play page used by script.js
+var playTexts = []string{
+	"Your turn",
+	"The robot is playing",
+	"Game over",
+	"Bag",
+	"tiles",
+	"Play",
+	"Recall",
+	"Shuffle",
+	"Exchange",
+	"Pass",
+	"Resign",
+	"Cancel",
+	"Choose the letter of the blank",
+	"Select the tiles to exchange",
+	"Do you want to resign?",
+	"Place tiles on the board",
+	"The tiles must be placed in one row or column",
+	"The placed tiles must form one word",
+	"%s gives %d points",
+	"passed",
+	"exchanged %d tiles",
+	"resigned",
+}
+
+type playData struct {
+	Lang      string
+	Scrabble  string
+	User      string
+	Play      string
+	MainMenu  string
+	SignOut   string
+	NewGame   string
+	Level     string
+	Levels    []autoplayLevel
+	Games     string
+	GameLinks []playGameLink
+	GameId    string
+	Token     string
+	Moves     string
+	Texts     map[string]string
+}
+
+type playGameLink struct {
+	Href  string
+	Title string
+}
+
+// playUser returns the signed in user - when no user is signed in the user is redirected to the sign in page
+func playUser(server *Server, w http.ResponseWriter, req *http.Request) *User {
+	user := server.signedInUser(req)
+	if user == nil {
+		http.Redirect(w, req, "/scrabble/signin?next="+url.QueryEscape(req.URL.Path), http.StatusSeeOther)
+	}
+	return user
+}
+
+func newPlayData(scrabble *Scrabble, user *User) playData {
+	lang := scrabble.options.Language
+	return playData{
+		Lang:     lang.String(),
+		Scrabble: Localized(lang, "Scrabble"),
+		User:     user.Name,
+		Play:     Localized(lang, "Play against a robot"),
+		MainMenu: Localized(lang, "Top level menu"),
+		SignOut:  Localized(lang, "Sign out"),
+	}
+}
+
+// playWWW shows the games of the signed in user and the form creating a new game
+func playWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	user := playUser(server, w, req)
+	if user == nil {
+		return
+	}
+	scrabble := getScrabble(server)
+	lang := scrabble.options.Language
+	data := newPlayData(scrabble, user)
+	data.NewGame = Localized(lang, "New game")
+	data.Level = Localized(lang, "Robot level")
+	data.Games = Localized(lang, "Your games")
+	current := scrabble.options.BotLevels.Level(1)
+	for _, level := range AllBotLevels {
+		data.Levels = append(data.Levels, autoplayLevel{Value: level.String(), Name: Localized(lang, level.String()), Selected: level == current})
+	}
+	for _, g := range server.games.owned(user.ID) {
+		g.Lock()
+		view := g.game.View(NoPlayer)
+		state := Localized(lang, "Your turn")
+		if view.Ended {
+			state = Localized(lang, "Game over")
+		}
+		title := fmt.Sprintf("%s %s", g.created.Format("2006-01-02 15:04"), state)
+		for _, player := range view.Players {
+			title += fmt.Sprintf(" - %s %d", player.Name, player.Score)
+		}
+		g.Unlock()
+		data.GameLinks = append(data.GameLinks, playGameLink{Href: "/scrabble/play/" + g.id, Title: title})
+	}
+	scrabble.templates.WriteTemplate(w, "play.html", data)
+}
+
+// playCreateWWW creates a game of the signed in user against a robot of the posted level
+func playCreateWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	user := playUser(server, w, req)
+	if user == nil {
+		return
+	}
+	scrabble := getScrabble(server)
+	level, err := ParseBotLevel(req.FormValue("level"))
+	if err != nil {
+		scrabble.templates.WriteError(w, err.Error())
+		return
+	}
+	request := apiCreateRequest{
+		Language: options.Language.String(),
+		Players:  []apiPlayerRequest{{Name: user.Name}, {Bot: level.String()}},
+	}
+	g, result := createApiGame(server, options, &request, user.ID)
+	if g == nil {
+		scrabble.templates.WriteError(w, result.Error.Message)
+		return
+	}
+	http.Redirect(w, req, "/scrabble/play/"+g.id, http.StatusSeeOther)
+}
+
+// playGameWWW shows a game of the signed in user - script.js plays it with the json api
+func playGameWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	user := playUser(server, w, req)
+	if user == nil {
+		return
+	}
+	scrabble := getScrabble(server)
+	lang := scrabble.options.Language
+	g, err := server.games.get(req.PathValue("id"))
+	if err != nil || g.owner != user.ID {
+		w.WriteHeader(http.StatusNotFound)
+		scrabble.templates.WriteError(w, fmt.Sprintf(Localized(lang, "You have no game %s"), req.PathValue("id")))
+		return
+	}
+	data := newPlayData(scrabble, user)
+	data.GameId = g.id
+	data.Moves = Localized(lang, "Moves")
+	data.Texts = make(map[string]string, len(playTexts))
+	for _, text := range playTexts {
+		data.Texts[text] = Localized(lang, text)
+	}
+	g.Lock()
+	for token, playerNo := range g.tokens {
+		if playerNo == 1 {
+			data.Token = token
+		}
+	}
+	g.Unlock()
+	scrabble.templates.WriteTemplate(w, "play.html", data)
+}
+
+// owned returns the games of the signed in user owner - the latest first
+func (games *apiGames) owned(owner uint64) []*apiGame {
+	games.Lock()
+	defer games.Unlock()
+	owned := make([]*apiGame, 0)
+	for _, g := range games.games {
+		if g.owner == owner {
+			owned = append(owned, g)
+		}
+	}
+	slices.SortFunc(owned, func(a, b *apiGame) int { return cmp.Compare(b.created.UnixNano(), a.created.UnixNano()) })
+	return owned
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"strings"
+	. "wordfeud/corpus"
 	. "wordfeud/game"
 )
 
@@ -56,26 +58,104 @@ func (w ActionResultErrorLogger) WriteString(str string) (int, error) {
 
 type CorpusResult struct {
 	ActionResult
-	Words          []string `json:"words"`
-	WordCount      int      `json:"wordCount"`      // total number of words - i.e. len(Words)
-	MinWordLength  int      `json:"minWordLength"`  // the shortest word in Words
-	MaxWordLength  int      `json:"maxWordLength"`  // the longest word in Words
-	TotalWordsSize int      `json:"totalWordsSize"` // the total number of characters in all word in Words
+	Words            []string            `json:"words"`
+	WordCount        int                 `json:"wordCount"`              // total number of words - i.e. len(Words)
+	MinWordLength    int                 `json:"minWordLength"`          // the shortest word in Words
+	MaxWordLength    int                 `json:"maxWordLength"`          // the longest word in Words
+	TotalWordsSize   int                 `json:"totalWordsSize"`         // the total number of characters in all word in Words
+	Filtered         map[string]int      `json:"filtered,omitempty"`     // the number of words removed by each filter rule
+	LengthCounts     map[int]int         `json:"lengthCounts,omitempty"` // the number of words of each length
+	LetterCounts     map[string]int      `json:"letterCounts,omitempty"` // the number of occurrences of each letter in all words
+	Bigrams          map[string]int      `json:"bigrams,omitempty"`      // the number of occurrences of each pair of letters
+	TwoLetterWords   int                 `json:"twoLetterWords,omitempty"`
+	ThreeLetterWords int                 `json:"threeLetterWords,omitempty"`
+	BingoWords       int                 `json:"bingoWords,omitempty"`   // the words of 7 and 8 letters which can use the whole rack
+	Sources          []string            `json:"sources,omitempty"`      // the word lists of the dictionary
+	Provenance       map[string]int      `json:"provenance,omitempty"`   // the number of words by the sources holding them - e.g. "ddo+ods"
+	ImportedFile     string              `json:"importedFile,omitempty"` // the corpus file written by corpus import
+	RejectFile       string              `json:"rejectFile,omitempty"`   // the lines of the sources which are not words
+	MetadataFile     string              `json:"metadataFile,omitempty"` // the lemma, part of speech, frequency rank and gloss of the words
+	Rejected         int                 `json:"rejected,omitempty"`
+	Duplicates       int                 `json:"duplicates,omitempty"`
+	Added            []string            `json:"added,omitempty"`   // the words added by corpus diff
+	Removed          []string            `json:"removed,omitempty"` // the words removed by corpus diff
+	AddedByLength    map[int]int         `json:"addedByLength,omitempty"`
+	RemovedByLength  map[int]int         `json:"removedByLength,omitempty"`
+	Played           map[string][]string `json:"played,omitempty"` // the game files holding each removed word
+	PatchFile        string              `json:"patchFile,omitempty"`
+	Tiles            []TileStat          `json:"tiles,omitempty"`          // the tile counts and values proposed by corpus tiles
+	Definition       string              `json:"definition,omitempty"`     // the language definition with the proposed tiles
+	DefinitionFile   string              `json:"definitionFile,omitempty"` // the file the language definition is written to
 }
 
 type GameResult struct {
 	ActionResult
-	Width        int          `json:"width"`
-	Height       int          `json:"height"`
-	LetterScores LetterScores `json:"pieceValues"`
-	Tiles        []rune       `json:"tiles"`
-	Board        *Board       `json:"board"`
+	Width        int           `json:"width"`
+	Height       int           `json:"height"`
+	LetterScores LetterScores  `json:"pieceValues"`
+	Tiles        []string      `json:"tiles"` // the letter of each piece value - a letter may be a digraph like CH
+	Board        *Board        `json:"board"`
+	Headers      []*GameHeader `json:"headers,omitempty"` // the headers of the checked game files
 }
 
 type DawgResult struct {
 	ActionResult
-	NodeCount   int `json:"nodeCount"`
-	VertexCount int `json:"vertexCount"`
+	NodeCount    int      `json:"nodeCount"`
+	VertexCount  int      `json:"vertexCount"`
+	CompiledFile string   `json:"compiledFile"`
+	Words        []string `json:"words"`
+}
+
+type WordResult struct {
+	ActionResult
+	Words     []string                `json:"words"`
+	Truncated bool                    `json:"truncated"` // more words than the requested limit were found
+	Anagrams  []AnagramGroupResult    `json:"anagrams"`
+	Hooks     []WordHooks             `json:"hooks"`
+	Metadata  map[string]WordMetadata `json:"metadata,omitempty"` // the lemma, part of speech, frequency rank and gloss of the words when known
+}
+
+type WordHooks struct {
+	Word       string   `json:"word"`
+	Valid      bool     `json:"valid"` // the word itself is in the dictionary
+	FrontHooks []string `json:"frontHooks"`
+	FrontWords []string `json:"frontWords"`
+	BackHooks  []string `json:"backHooks"`
+	BackWords  []string `json:"backWords"`
+}
+
+// ApiError is the error envelope of the json api - the http status, a short code for programs
+// and the message which is also in Err
+type ApiError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ApiPlayerToken struct {
+	Player PlayerNo `json:"player"`
+	Token  string   `json:"token"`
+}
+
+type ApiResult struct {
+	ActionResult
+	Error   *ApiError        `json:"error,omitempty"`
+	GameId  string           `json:"gameId,omitempty"`
+	Tokens  []ApiPlayerToken `json:"tokens,omitempty"` // the tokens of the human players - only when the game is created
+	Game    *GameView        `json:"game,omitempty"`
+	Moves   []MoveView       `json:"moves,omitempty"`
+	Preview *MovePreview     `json:"preview,omitempty"` // the score and the words of a validated move
+}
+
+type AnagramWord struct {
+	Word  string `json:"word"`
+	Score Score  `json:"score"`
+}
+
+type AnagramGroupResult struct {
+	Length int           `json:"length"`
+	Full   bool          `json:"full"` // the words use all tiles of the rack
+	Words  []AnagramWord `json:"words"`
 }
 
 func (a *ActionResult) logger() ActionResultLogger {
@@ -104,3 +184,20 @@ func (r *DawgResult) result() *DawgResult {
 	r.setResult()
 	return r
 }
+
+func (r *WordResult) result() *WordResult {
+	r.setResult()
+	return r
+}
+
+func (r *ApiResult) result() *ApiResult {
+	r.setResult()
+	return r
+}
+
+// fail makes r the error envelope of err
+func (r *ApiResult) fail(status int, code string, err error) *ApiResult {
+	fmt.Fprintln(r.errors(), err.Error())
+	r.Error = &ApiError{Status: status, Code: code, Message: err.Error()}
+	return r.result()
+}
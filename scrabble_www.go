@@ -3,12 +3,17 @@ package main
 import (
 	_ "embed"
 	"net/http"
+	"os"
+	"path"
+	"sync"
 	. "wordfeud/context"
+	. "wordfeud/game"
 	. "wordfeud/localize"
 	. "wordfeud/template"
 )
 
 type Scrabble struct {
+	once        sync.Once
 	options     *GameOptions
 	callCount   int
 	seqno       int
@@ -18,7 +23,6 @@ type Scrabble struct {
 }
 
 var scrabbleData = Scrabble{
-	options:   nil,
 	callCount: 0,
 	seqno:     1,
 	templates: nil,
@@ -26,29 +30,34 @@ var scrabbleData = Scrabble{
 }
 
 func getScrabble(server *Server) *Scrabble {
-	if scrabbleData.options == nil {
-		scrabbleData.init(server)
-	}
+	scrabbleData.once.Do(func() { scrabbleData.init(server) })
 	return &scrabbleData
 }
 
 func (scrabble *Scrabble) init(server *Server) {
-	scrabble.options = server.serviceOptions
+	scrabble.options = server.options.Copy()
 	scrabble.templates = CreateTemplates(scrabble.options.Language)
 	scrabble.options.FileFormat = FILE_FORMAT_WWW
-	scrabble.options.Directory = "www"
+	scrabble.options.Directory = scrabble.wwwDir
+	// the styles of the board of the play page are the styles of the html game files
+	os.MkdirAll(path.Join(scrabble.options.Directory, "game"), 0755)
 	scrabble.templates.WriteTemplateScript(scrabble.options.Directory)
 	scrabble.templates.WriteTemplateStyles(scrabble.options.Directory)
+	WriteHtmlStyles(path.Join(scrabble.options.Directory, "game"))
 }
 
-func scrabbleWWW(server *Server, w http.ResponseWriter, req *http.Request) {
-	userName := ""
+func scrabbleWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
 	scrabble := getScrabble(server)
 	scrabble.callCount++
 	lang := scrabble.options.Language
 	data := IndexData{
+		Lang:     lang.String(),
 		Scrabble: Localized(lang, "Scrabble"),
-		User:     userName,
+		User:     server.signedInUserName(req),
+		Autoplay: Localized(lang, "Two robot player game"),
+		Play:     Localized(lang, "Play against a robot"),
+		SignIn:   Localized(lang, "Sign in"),
+		SignOut:  Localized(lang, "Sign out"),
 	}
 	scrabble.templates.WriteTemplate(w, "index.html", data)
 }
@@ -6,72 +6,75 @@ import (
 	"net/http"
 	"strconv"
 	. "wordfeud/context"
+	. "wordfeud/store"
 
 	"golang.org/x/text/language"
 )
 
 type Server struct {
-	options        *GameOptions
-	serviceOptions *GameOptions
+	options  *GameOptions // the options of the server - each request gets a copy
+	games    *apiGames
+	store    Store // the users - nil when the store cannot be opened
+	sessions *userSessions
 }
 
-type serverEndpoint func(server *Server, w http.ResponseWriter, req *http.Request)
+type serverEndpoint func(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request)
 type endpointFunc func(w http.ResponseWriter, req *http.Request)
 
 func endpointWrapper(server *Server, f serverEndpoint) endpointFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		server.serviceOptions = server.options.Copy()
-		server.serviceOptions.Out = w
+		options := server.options.Copy()
+		options.Out = w
 
 		query := req.URL.Query()
 		if s, ok := query["v"]; ok {
 			v, err := strconv.Atoi(s[0])
 			if err == nil {
-				server.serviceOptions.Verbose = v > 0
+				options.Verbose = v > 0
 			}
 		}
 		if s, ok := query["d"]; ok {
 			d, err := strconv.Atoi(s[0])
 			if err == nil {
-				server.serviceOptions.Debug = uint(d)
+				options.Debug = uint(d)
 			}
 		}
 		if s, ok := query["l"]; ok {
 			tag, err := language.Default.Parse(s[0])
 			if err == nil {
-				server.serviceOptions.Language = tag
+				options.Language = tag
 			}
 		}
 		if s, ok := query["r"]; ok {
 			r, err := strconv.ParseUint(s[0], 10, 64)
 			if err == nil {
-				server.serviceOptions.RandSeed = r
+				options.RandSeed = r
 			}
 		}
 		if s, ok := query["n"]; ok {
-			server.serviceOptions.Name = s[0]
+			options.Name = s[0]
 		}
-		f(server, w, req)
+		f(server, options, w, req)
 	}
 }
 
-func _hello(server *Server, w http.ResponseWriter, req *http.Request) {
+func _hello(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
 
 	fmt.Fprintf(w, "hello\n")
-	if server.serviceOptions.Verbose {
-		fmt.Fprintf(w, "options: %+v\n", server.serviceOptions)
+	if options.Verbose {
+		fmt.Fprintf(w, "options: %+v\n", options)
 	}
 }
 
-func _headers(server *Server, w http.ResponseWriter, req *http.Request) {
+func _headers(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
 
 	for name, headers := range req.Header {
 		for _, h := range headers {
 			fmt.Fprintf(w, "%v: %v\n", name, h)
 		}
 	}
-	if server.serviceOptions.Verbose {
-		fmt.Fprintf(w, "options: %+v\n", server.serviceOptions)
+	if options.Verbose {
+		fmt.Fprintf(w, "options: %+v\n", options)
 	}
 }
 
@@ -94,14 +97,40 @@ func serveCmd(options *GameOptions, args []string) {
 		return
 	}
 
-	server := &Server{options, nil}
+	server := &Server{options: options, games: newApiGames(), sessions: newUserSessions()}
+	if store, err := OpenStore("users"); err != nil {
+		fmt.Fprintf(options.Out, "users cannot sign in as the user store cannot be opened : %v\n", err)
+	} else {
+		server.store = store
+		defer store.Defer()
+	}
 
-	http.Handle("/www/", http.StripPrefix("/www/", http.FileServer(http.Dir("./www"))))
-	http.HandleFunc("/hello/", endpointWrapper(server, _hello))
-	http.HandleFunc("/headers/", endpointWrapper(server, _headers))
-	http.HandleFunc("/scrabble/", endpointWrapper(server, scrabbleWWW))
-	http.HandleFunc("/scrabble/autoplay/", endpointWrapper(server, autoplayWWW))
-	http.HandleFunc("/scrabble/autoplay/game", endpointWrapper(server, autoplayGameWWW))
+	http.ListenAndServe(fmt.Sprintf(":%d", port), newServeMux(server))
+}
 
-	http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
+// newServeMux returns the endpoints of server
+func newServeMux(server *Server) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/www/", http.StripPrefix("/www/", http.FileServer(http.Dir("./www"))))
+	mux.HandleFunc("/hello/", endpointWrapper(server, _hello))
+	mux.HandleFunc("/headers/", endpointWrapper(server, _headers))
+	mux.HandleFunc("/scrabble/", endpointWrapper(server, scrabbleWWW))
+	mux.HandleFunc("/scrabble/autoplay/", endpointWrapper(server, autoplayWWW))
+	mux.HandleFunc("/scrabble/autoplay/game", endpointWrapper(server, autoplayGameWWW))
+	mux.HandleFunc("/scrabble/word/find", endpointWrapper(server, wordFindWWW))
+	mux.HandleFunc("/scrabble/word/info", endpointWrapper(server, wordInfoWWW))
+	mux.HandleFunc("/scrabble/corpus/stat", endpointWrapper(server, corpusStatWWW))
+	mux.HandleFunc("/scrabble/signin", endpointWrapper(server, signInWWW))
+	mux.HandleFunc("/scrabble/signout", endpointWrapper(server, signOutWWW))
+	mux.HandleFunc("GET /scrabble/play", endpointWrapper(server, playWWW))
+	mux.HandleFunc("POST /scrabble/play", endpointWrapper(server, playCreateWWW))
+	mux.HandleFunc("GET /scrabble/play/{id}", endpointWrapper(server, playGameWWW))
+	mux.HandleFunc("/api/v1/", endpointWrapper(server, apiNotFoundWWW))
+	mux.HandleFunc("POST /api/v1/games", endpointWrapper(server, apiCreateGameWWW))
+	mux.HandleFunc("GET /api/v1/games/{id}", endpointWrapper(server, apiGameWWW))
+	mux.HandleFunc("GET /api/v1/games/{id}/moves", endpointWrapper(server, apiMovesWWW))
+	mux.HandleFunc("POST /api/v1/games/{id}/moves", endpointWrapper(server, apiMoveWWW))
+	mux.HandleFunc("POST /api/v1/games/{id}/validate", endpointWrapper(server, apiValidateWWW))
+	mux.HandleFunc("POST /api/v1/games/{id}/resign", endpointWrapper(server, apiResignWWW))
+	return mux
 }
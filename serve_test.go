@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+	. "wordfeud/game"
+	. "wordfeud/store"
+
+	"golang.org/x/text/language"
+)
+
+// newTestServer returns the endpoints of a server playing danish with the words of data_test/dk_partial.txt
+func newTestServer(t *testing.T) (*Server, http.Handler) {
+	dir := t.TempDir()
+	definition, err := os.ReadFile("corpus/languages/da.json")
+	if err != nil {
+		t.Fatalf("newTestServer() cannot read the danish language definition : %v", err)
+	}
+	definition = []byte(strings.Replace(string(definition), `"corpus_dk.txt"`, `"../data_test/dk_partial.txt"`, 1))
+	if err := os.WriteFile(path.Join(dir, "da.json"), definition, 0644); err != nil {
+		t.Fatalf("newTestServer() cannot write the language definition : %v", err)
+	}
+	if err := LoadLanguages(dir); err != nil {
+		t.Fatalf("newTestServer() cannot load the languages : %v", err)
+	}
+	t.Cleanup(func() { LoadLanguages(LanguageDirectory) })
+	store, err := OpenStoreFile(dir, "users", true)
+	if err != nil {
+		t.Fatalf("newTestServer() cannot open the user store : %v", err)
+	}
+	t.Cleanup(store.Defer)
+	scrabbleData.wwwDir = path.Join(dir, "www")
+
+	options := &GameOptions{Out: io.Discard, Language: language.Danish, RandSeed: 1, Count: 1, Name: "scrabble"}
+	server := &Server{options: options, games: newApiGames(), store: store, sessions: newUserSessions()}
+	return server, newServeMux(server)
+}
+
+func serveTestRequest(handler http.Handler, method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func apiTestRequest(t *testing.T, handler http.Handler, method string, target string, token string, body string) (int, *ApiResult) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+	w := serveTestRequest(handler, method, target, body, header)
+	result := new(ApiResult)
+	if err := json.Unmarshal(w.Body.Bytes(), result); err != nil {
+		t.Errorf("%s %s - the response is not an ApiResult : %v\n%s", method, target, err, w.Body.String())
+	}
+	return w.Code, result
+}
+
+func Test_Api(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	status, result := apiTestRequest(t, handler, "POST", "/api/v1/games", "", `{"seed": 42}`)
+	if status != http.StatusCreated || result.Error != nil || result.GameId == "" || len(result.Tokens) != 1 {
+		t.Fatalf("Test_Api() create game : %d %+v", status, result.Error)
+	}
+	id := result.GameId
+	token := result.Tokens[0].Token
+	if result.Game.Seed != 42 || result.Game.NextPlayer != 1 || len(result.Game.Players[0].Rack) != RackSize {
+		t.Errorf("Test_Api() the created game has the seed %d, the next player %d and the rack %v", result.Game.Seed, result.Game.NextPlayer, result.Game.Players[0].Rack)
+	}
+
+	status, result = apiTestRequest(t, handler, "GET", "/api/v1/games/"+id, token, "")
+	if status != http.StatusOK || result.Game == nil || len(result.Game.Players[0].Rack) != RackSize {
+		t.Fatalf("Test_Api() state of game %s : %d %+v", id, status, result.Error)
+	}
+	if result.Game.Seed != 0 {
+		t.Errorf("Test_Api() the state of the running game %s shows the seed %d", id, result.Game.Seed)
+	}
+	rack := result.Game.Players[0].Rack
+	if status, result := apiTestRequest(t, handler, "GET", "/api/v1/games/"+id, "", ""); status != http.StatusOK || len(result.Game.Players[0].Rack) != 0 {
+		t.Errorf("Test_Api() the rack of player 1 is shown without a token : %d %v", status, result.Game.Players[0].Rack)
+	}
+
+	type errorCase struct {
+		method string
+		target string
+		token  string
+		body   string
+		status int
+		code   string
+	}
+	errorCases := []errorCase{
+		{"POST", "/api/v1/games", "", `{"players": [`, http.StatusBadRequest, "bad_request"},
+		{"POST", "/api/v1/games", "", `{"players": [{"bot": "expert"}, {"bot": "expert"}]}`, http.StatusBadRequest, "bad_request"},
+		{"POST", "/api/v1/games", "", `{"players": [{"name": "` + strings.Repeat("a", apiRequestMax) + `"}, {"bot": "expert"}]}`, http.StatusBadRequest, "bad_request"},
+		{"GET", "/api/v1/games/unknown", "", "", http.StatusNotFound, "not_found"},
+		{"GET", "/api/v1/nothing", "", "", http.StatusNotFound, "not_found"},
+		{"POST", "/api/v1/games/" + id + "/moves", token, `{"kind": "pass"`, http.StatusBadRequest, "bad_request"},
+		{"POST", "/api/v1/games/" + id + "/moves", token, `{"kind": "pass", "word": "` + strings.Repeat("a", apiRequestMax) + `"}`, http.StatusBadRequest, "bad_request"},
+		{"POST", "/api/v1/games/" + id + "/moves", "", `{"kind": "pass"}`, http.StatusUnauthorized, "unauthorized"},
+		{"POST", "/api/v1/games/" + id + "/moves", "bad", `{"kind": "pass"}`, http.StatusForbidden, "forbidden"},
+		{"POST", "/api/v1/games/" + id + "/moves", token, `{"row": 7, "column": 7, "orientation": "h", "word": "ØØØ"}`, http.StatusUnprocessableEntity, "invalid_move"},
+	}
+	for _, c := range errorCases {
+		status, result := apiTestRequest(t, handler, c.method, c.target, c.token, c.body)
+		if status != c.status || result.Error == nil || result.Error.Code != c.code || result.Error.Status != c.status {
+			t.Errorf("Test_Api() %s %s %.40s : %d %+v - expected %d %s", c.method, c.target, c.body, status, result.Error, c.status, c.code)
+		}
+	}
+
+	status, result = apiTestRequest(t, handler, "POST", "/api/v1/games/"+id+"/moves", token, `{"kind": "exchange", "tiles": "`+rack[0]+`"}`)
+	if status != http.StatusOK || result.Error != nil {
+		t.Fatalf("Test_Api() exchange in game %s : %d %+v", id, status, result.Error)
+	}
+	if len(result.Moves) != 2 || result.Moves[0].Kind != "exchange" || result.Moves[1].Player != 2 || result.Game.NextPlayer != 1 {
+		t.Errorf("Test_Api() the exchange and the move of the bot are %+v", result.Moves)
+	}
+	status, result = apiTestRequest(t, handler, "GET", "/api/v1/games/"+id+"/moves", "", "")
+	if status != http.StatusOK || len(result.Moves) != 2 {
+		t.Errorf("Test_Api() moves of game %s : %d %+v", id, status, result.Moves)
+	}
+}
+
+func Test_SignIn(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	w := serveTestRequest(handler, "GET", "/scrabble/play", "", nil)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/scrabble/signin?next="+url.QueryEscape("/scrabble/play") {
+		t.Errorf("Test_SignIn() the play page without a signed in user answers %d %s", w.Code, w.Header().Get("Location"))
+	}
+
+	form := http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}
+	signIn := url.Values{"name": {"anna"}, "password": {"secret"}, "next": {"/scrabble/play"}}
+	w = serveTestRequest(handler, "POST", "/scrabble/signin", signIn.Encode(), form)
+	if w.Code != http.StatusOK || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Test_SignIn() an unknown user was signed in : %d", w.Code)
+	}
+	signIn.Set("create", "1")
+	w = serveTestRequest(handler, "POST", "/scrabble/signin", signIn.Encode(), form)
+	cookies := w.Result().Cookies()
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/scrabble/play" || len(cookies) != 1 || cookies[0].Name != sessionCookieName {
+		t.Fatalf("Test_SignIn() create user answers %d %s %v", w.Code, w.Header().Get("Location"), cookies)
+	}
+	signIn.Del("create")
+	signIn.Set("password", "wrong")
+	w = serveTestRequest(handler, "POST", "/scrabble/signin", signIn.Encode(), form)
+	if w.Code != http.StatusOK || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Test_SignIn() a user was signed in with a wrong password : %d", w.Code)
+	}
+
+	session := http.Header{"Cookie": {cookies[0].String()}}
+	w = serveTestRequest(handler, "GET", "/scrabble/play", "", session)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "anna") || !strings.Contains(w.Body.String(), `<html lang="da">`) {
+		t.Errorf("Test_SignIn() the play page of the signed in user answers %d", w.Code)
+	}
+	session.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = serveTestRequest(handler, "POST", "/scrabble/play", "level=beginner", session)
+	location := w.Header().Get("Location")
+	if w.Code != http.StatusSeeOther || !strings.HasPrefix(location, "/scrabble/play/") {
+		t.Fatalf("Test_SignIn() create game answers %d %s", w.Code, location)
+	}
+	if w = serveTestRequest(handler, "GET", location, "", session); w.Code != http.StatusOK {
+		t.Errorf("Test_SignIn() the game page %s answers %d", location, w.Code)
+	}
+	if w = serveTestRequest(handler, "GET", location, "", nil); w.Code != http.StatusSeeOther {
+		t.Errorf("Test_SignIn() the game page %s without a signed in user answers %d", location, w.Code)
+	}
+
+	serveTestRequest(handler, "GET", "/scrabble/signout", "", session)
+	if w = serveTestRequest(handler, "GET", "/scrabble/play", "", session); w.Code != http.StatusSeeOther {
+		t.Errorf("Test_SignIn() the session was not ended by sign out : %d", w.Code)
+	}
+}
+
+func Test_WordFind(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	// the limit of the words found is 1 .. wordFindDefaultLimit - the dictionary has more words
+	limits := map[string]int{"": wordFindDefaultLimit, "0": 1, "-1": 1, "2": 2, "1000000": wordFindDefaultLimit}
+	for limit, expected := range limits {
+		w := serveTestRequest(handler, "GET", "/scrabble/word/find?p=*&limit="+limit, "", nil)
+		result := new(WordResult)
+		if err := json.Unmarshal(w.Body.Bytes(), result); err != nil || w.Code != http.StatusOK {
+			t.Errorf("Test_WordFind() limit %s answers %d : %v", limit, w.Code, err)
+			continue
+		}
+		if len(result.Words) != expected || !result.Truncated {
+			t.Errorf("Test_WordFind() limit %s found %d words - expected %d", limit, len(result.Words), expected)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	. "wordfeud/context"
+	. "wordfeud/localize"
+	. "wordfeud/store"
+)
+
+// the name of the cookie holding the session of a signed in user
+const sessionCookieName = "wordfeud-session"
+
+type userSessions struct {
+	sync.Mutex
+	users map[string]uint64 // the user id of each session token
+}
+
+type signInData struct {
+	Lang       string
+	Scrabble   string
+	User       string
+	SignIn     string
+	CreateUser string
+	UserName   string
+	Password   string
+	Mail       string
+	MainMenu   string
+	Next       string
+	Error      string
+}
+
+func newUserSessions() *userSessions {
+	return &userSessions{users: make(map[string]uint64)}
+}
+
+func (sessions *userSessions) add(userId uint64) string {
+	sessions.Lock()
+	defer sessions.Unlock()
+	token := newApiToken(16)
+	sessions.users[token] = userId
+	return token
+}
+
+func (sessions *userSessions) lookup(token string) (uint64, bool) {
+	sessions.Lock()
+	defer sessions.Unlock()
+	userId, found := sessions.users[token]
+	return userId, found
+}
+
+func (sessions *userSessions) remove(token string) {
+	sessions.Lock()
+	defer sessions.Unlock()
+	delete(sessions.users, token)
+}
+
+// signedInUser returns the user of the session of req - nil when no user is signed in
+func (server *Server) signedInUser(req *http.Request) *User {
+	if server.store == nil {
+		return nil
+	}
+	cookie, err := req.Cookie(sessionCookieName)
+	if err != nil {
+		return nil
+	}
+	userId, found := server.sessions.lookup(cookie.Value)
+	if !found {
+		return nil
+	}
+	return server.store.LookupUser(userId)
+}
+
+// signedInUserName returns the name of the signed in user - empty when no user is signed in
+func (server *Server) signedInUserName(req *http.Request) string {
+	if user := server.signedInUser(req); user != nil {
+		return user.Name
+	}
+	return ""
+}
+
+// signInWWW shows the sign in form and signs in (or creates) the user of a posted form
+func signInWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	scrabble := getScrabble(server)
+	lang := scrabble.options.Language
+	data := signInData{
+		Lang:       lang.String(),
+		Scrabble:   Localized(lang, "Scrabble"),
+		SignIn:     Localized(lang, "Sign in"),
+		CreateUser: Localized(lang, "Create user"),
+		UserName:   Localized(lang, "User name"),
+		Password:   Localized(lang, "Password"),
+		Mail:       Localized(lang, "Mail (optional)"),
+		MainMenu:   Localized(lang, "Top level menu"),
+		Next:       req.FormValue("next"),
+	}
+	if !strings.HasPrefix(data.Next, "/scrabble") {
+		data.Next = "/scrabble"
+	}
+	if req.Method == http.MethodPost {
+		if server.store == nil {
+			data.Error = Localized(lang, "Users cannot sign in as the user store is not available")
+		} else {
+			name := strings.TrimSpace(req.FormValue("name"))
+			password := req.FormValue("password")
+			var user *User
+			var err error
+			if req.FormValue("create") != "" {
+				user, err = server.store.CreateUser(name, password, strings.TrimSpace(req.FormValue("mail")))
+			} else {
+				user, err = server.store.SignInUser(name, password)
+			}
+			if err == nil {
+				http.SetCookie(w, &http.Cookie{
+					Name:     sessionCookieName,
+					Value:    server.sessions.add(user.ID),
+					Path:     "/",
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+				http.Redirect(w, req, data.Next, http.StatusSeeOther)
+				return
+			}
+			if serr := AsStoreError(err); serr != nil {
+				err = serr.Err
+			}
+			data.Error = fmt.Sprint(err)
+		}
+	}
+	data.User = server.signedInUserName(req)
+	scrabble.templates.WriteTemplate(w, "signin.html", data)
+}
+
+// signOutWWW ends the session of the signed in user
+func signOutWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	if cookie, err := req.Cookie(sessionCookieName); err == nil {
+		server.sessions.remove(cookie.Value)
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "", Path: "/", MaxAge: -1})
+	http.Redirect(w, req, "/scrabble", http.StatusSeeOther)
+}
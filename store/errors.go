@@ -16,6 +16,7 @@ const (
 	STORE_ERROR_USER_NAME_SHORT     = 101
 	STORE_ERROR_USER_PASSWORD_SHORT = 102
 	STORE_ERROR_USER_NAME_EXISTS    = 103
+	STORE_ERROR_USER_SIGN_IN        = 104
 )
 
 func (serr *StoreError) Error() string {
@@ -17,6 +17,7 @@ type Store interface {
 	DeleteUser(uint64) error
 	LookupUser(uint64) *User
 	LookupUserByName(string) *User
+	SignInUser(string, string) (*User, error)
 }
 
 type _Store struct {
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"strings"
 	"time"
@@ -79,6 +80,16 @@ func (store *_Store) LookupUserByName(name string) *User {
 	return user
 }
 
+// SignInUser returns the user of name when password is the password of the user
+func (store *_Store) SignInUser(name string, password string) (*User, error) {
+	user := store.LookupUserByName(name)
+	hash := CryptoHash(password)
+	if user == nil || subtle.ConstantTimeCompare([]byte(user.PasswordHash), []byte(hash)) != 1 {
+		return nil, NewStoreError(STORE_ERROR_USER_SIGN_IN, "unknown user name or wrong password")
+	}
+	return user, nil
+}
+
 func (store *_Store) DeleteUser(ID uint64) error {
 	return store.db.Delete(ID, User{})
 }
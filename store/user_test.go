@@ -52,6 +52,17 @@ func Test_CreateUser(t *testing.T) {
 			return
 		}
 	}
+	for i, u := range users {
+		user, err := store.SignInUser(u.Name, createUsers[i].password)
+		if err != nil || user.ID != u.ID {
+			t.Errorf("Test_CreateUser - sign in of User %v failed : %v", u.Name, err)
+			return
+		}
+		if _, err := store.SignInUser(u.Name, "funnyPW"); StoreErrorCode(err) != STORE_ERROR_USER_SIGN_IN {
+			t.Errorf("Test_CreateUser - User %v signed in with a wrong password", u.Name)
+			return
+		}
+	}
 	for _, u := range users {
 		user, err := store.CreateUser(u.Name, "funnyPW", u.TentativeMail.Value)
 		if err == nil {
@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	html "html/template"
 	"io"
+	"io/fs"
 	"os"
 	"path"
 	text "text/template"
@@ -12,17 +13,24 @@ import (
 )
 
 type IndexData struct {
+	Lang     string // the language of the page
 	Scrabble string
-	User     string
+	User     string // empty when no user is signed in
+	Autoplay string
+	Play     string
+	SignIn   string
+	SignOut  string
 }
 
 type AutoplayData struct {
+	Lang     string
 	Scrabble string
 	User     string
 	Autoplay string
 }
 
 type ErrorData struct {
+	Lang  string
 	Error string
 }
 
@@ -34,16 +42,26 @@ type Templates interface {
 }
 
 type _Templates struct {
-	directory string
-	text      *text.Template
-	html      *html.Template
+	language language.Tag // the language of the pages
+	files    fs.FS
+	text     *text.Template
+	html     *html.Template
+}
+
+// the template files - the directory "templates" unless embedded files are given by EmbedTemplates
+var templateFiles fs.FS = os.DirFS("templates")
+
+// EmbedTemplates makes the templates be read from fsys instead of the directory "templates"
+func EmbedTemplates(fsys fs.FS) {
+	templateFiles = fsys
 }
 
 func CreateTemplates(language language.Tag) Templates {
 	templates := &_Templates{
-		directory: "templates",
+		language: language,
+		files:    templateFiles,
 	}
-	templates.html = html.Must(html.ParseGlob(path.Join(templates.directory, "*.html")))
+	templates.html = html.Must(html.ParseFS(templates.files, "*.html"))
 	//templates.text = text.Must(text.ParseGlob(path.Join(templates.directory, "*.text")))
 	return templates
 }
@@ -74,7 +92,7 @@ func (templates *_Templates) WriteError(w io.Writer, error string) {
 	if t == nil {
 		panic(`Cannot locate template "error.html"` + "\n" + error)
 	}
-	data := ErrorData{error}
+	data := ErrorData{Lang: templates.language.String(), Error: error}
 	if err := t.Execute(w, data); err != nil {
 		panic(`Error executing template "error.html"` + "\n" + err.Error())
 	}
@@ -84,13 +102,12 @@ func (templates *_Templates) CopyTemplateFile(dirName string, fileName string) e
 	var err error
 	var content []byte
 
-	sourceFilePath := path.Join(templates.directory, fileName)
 	destinationFilePath := path.Join(dirName, fileName)
 	destinationTmpFilePath := destinationFilePath + "~"
 	defer func() {
 		os.Remove(destinationTmpFilePath)
 	}()
-	if content, err = os.ReadFile(sourceFilePath); err != nil {
+	if content, err = fs.ReadFile(templates.files, fileName); err != nil {
 		return err
 	}
 	if err = os.WriteFile(destinationTmpFilePath, content, 0644); err != nil {
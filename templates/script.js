@@ -0,0 +1,484 @@
+"use strict";
+
+// the play page (play.html) - a game of the signed in user against a robot played with the json api /api/v1
+
+const play = {
+    game: "",
+    token: "",
+    playerNo: 0,
+    view: null,
+    rack: [],       // the tiles of the rack - {letter, blank} where letter "?" is a blank and blank is its chosen letter
+    placed: [],     // the tiles placed on the board in this turn - {tile, row, column}
+    selected: null, // the rack tile to place by a click on the board
+    dragged: null,
+    exchange: null, // the tiles selected for an exchange - null when not exchanging
+    action: null,   // the move of the placed tiles when it is valid
+    previews: 0,
+    busy: false,
+};
+
+function text(s) {
+    return (window.playTexts && window.playTexts[s]) || s;
+}
+
+function format(s, ...args) {
+    let i = 0;
+    return s.replace(/%[sd]/g, () => args[i++]);
+}
+
+async function api(method, path, body) {
+    const response = await fetch(`/api/v1/games/${play.game}${path}`, {
+        method: method,
+        headers: { "Authorization": `Bearer ${play.token}`, "Content-Type": "application/json" },
+        body: body ? JSON.stringify(body) : undefined,
+    });
+    return response.json();
+}
+
+window.addEventListener("DOMContentLoaded", () => {
+    const page = document.getElementById("play");
+    if (!page) {
+        return;
+    }
+    play.game = page.dataset.game;
+    play.token = page.dataset.token;
+    play.playerNo = Number(page.dataset.player);
+    document.getElementById("play-move").addEventListener("click", playMove);
+    document.getElementById("play-recall").addEventListener("click", recallTiles);
+    document.getElementById("play-shuffle").addEventListener("click", shuffleRack);
+    document.getElementById("play-exchange").addEventListener("click", exchangeTiles);
+    document.getElementById("play-cancel").addEventListener("click", cancelExchange);
+    document.getElementById("play-pass").addEventListener("click", () => submit("/moves", { kind: "pass" }));
+    document.getElementById("play-resign").addEventListener("click", () => {
+        if (window.confirm(text("Do you want to resign?"))) {
+            submit("/resign", null);
+        }
+    });
+    const rack = document.getElementById("rack");
+    rack.addEventListener("dragover", (event) => event.preventDefault());
+    rack.addEventListener("drop", (event) => {
+        event.preventDefault();
+        if (play.dragged) {
+            returnTile(play.dragged);
+        }
+    });
+    load();
+});
+
+async function load() {
+    const result = await api("GET", "");
+    if (result.error) {
+        showMessage(result.error.message);
+        return;
+    }
+    setView(result.game);
+    const moves = await api("GET", "/moves");
+    document.getElementById("moves").replaceChildren();
+    addMoves(moves.moves || []);
+}
+
+function setView(view) {
+    const me = view.players.find((player) => player.no === play.playerNo);
+    play.view = view;
+    play.rack = ((me && me.rack) || []).map((letter) => ({ letter: letter, blank: "" }));
+    play.placed = [];
+    play.selected = null;
+    play.exchange = null;
+    play.action = null;
+    render();
+}
+
+function myTurn() {
+    return play.view && !play.view.ended && play.view.nextPlayer === play.playerNo && !play.busy;
+}
+
+function render() {
+    renderStatus();
+    renderPlayers();
+    renderBoard();
+    renderRack();
+    const exchanging = play.exchange !== null;
+    document.getElementById("play-move").disabled = !myTurn() || exchanging || play.action === null;
+    document.getElementById("play-recall").disabled = !myTurn() || play.placed.length === 0;
+    document.getElementById("play-shuffle").disabled = play.view.ended;
+    document.getElementById("play-exchange").disabled = !myTurn() || (exchanging && play.exchange.length === 0);
+    document.getElementById("play-cancel").classList.toggle("hidden", !exchanging);
+    document.getElementById("play-pass").disabled = !myTurn() || exchanging;
+    document.getElementById("play-resign").disabled = !myTurn() || exchanging;
+}
+
+function renderStatus() {
+    const view = play.view;
+    const status = document.getElementById("status");
+    let s = `${text("Bag")}: ${view.bagSize} ${text("tiles")} - `;
+    if (view.ended) {
+        s += text("Game over");
+    } else if (view.nextPlayer === play.playerNo) {
+        s += text("Your turn");
+    } else {
+        s += text("The robot is playing");
+    }
+    status.replaceChildren(s);
+    for (const line of view.result || []) {
+        const div = document.createElement("div");
+        div.textContent = line;
+        status.append(div);
+    }
+}
+
+function renderPlayers() {
+    const players = document.getElementById("players");
+    players.replaceChildren();
+    for (const player of play.view.players) {
+        const row = players.insertRow();
+        row.className = "player";
+        const name = row.insertCell();
+        name.className = "name";
+        name.textContent = player.level ? `${player.name} (${player.level})` : player.name;
+        const score = row.insertCell();
+        score.className = "total-score";
+        score.textContent = player.score;
+        if (player.no === play.view.nextPlayer) {
+            name.classList.add("next");
+        }
+    }
+}
+
+// letterScore returns the score of a letter of the board - a blank (lower case) scores 0
+function letterScore(letter) {
+    if (letter !== letter.toUpperCase()) {
+        return 0;
+    }
+    return play.view.letterScores[letter] || 0;
+}
+
+function tileLetter(tile) {
+    return tile.letter === "?" ? tile.blank.toLowerCase() : tile.letter;
+}
+
+function placedAt(row, column) {
+    return play.placed.find((p) => p.row === row && p.column === column);
+}
+
+function letterAt(row, column) {
+    if (row < 0 || column < 0 || row >= play.view.board.length || column >= play.view.board[row].length) {
+        return "";
+    }
+    const p = placedAt(row, column);
+    return p ? tileLetter(p.tile) : play.view.board[row][column];
+}
+
+// tileElement returns a tile shown as the tiles of the html game files
+function tileElement(className, letter, score) {
+    const div = document.createElement("div");
+    div.className = className;
+    div.append(letter);
+    const span = document.createElement("span");
+    span.className = "score";
+    span.textContent = score;
+    div.append(span);
+    return div;
+}
+
+function renderBoard() {
+    const view = play.view;
+    const table = document.createElement("table");
+    table.className = "board";
+    const header = table.insertRow();
+    header.append(headerCell("thh", ""));
+    for (let c = 0; c < view.board[0].length; c++) {
+        header.append(headerCell("thh", c));
+    }
+    for (let r = 0; r < view.board.length; r++) {
+        const row = table.insertRow();
+        row.append(headerCell("thv", r));
+        for (let c = 0; c < view.board[r].length; c++) {
+            const td = row.insertCell();
+            const square = document.createElement("div");
+            const kind = view.squares[r][c] === "CE" ? "ct" : view.squares[r][c].toLowerCase();
+            square.className = kind ? `square ${kind}` : "square";
+            const p = placedAt(r, c);
+            if (p) {
+                const tile = tileElement("played", tileLetter(p.tile).toUpperCase(), letterScore(tileLetter(p.tile)));
+                tile.draggable = true;
+                tile.addEventListener("dragstart", () => { play.dragged = p.tile; });
+                square.append(tile);
+            } else if (view.board[r][c]) {
+                square.append(tileElement("tile", view.board[r][c].toUpperCase(), letterScore(view.board[r][c])));
+            }
+            td.append(square);
+            td.addEventListener("click", () => clickSquare(r, c));
+            td.addEventListener("dragover", (event) => {
+                if (!view.board[r][c] && !placedAt(r, c)) {
+                    event.preventDefault();
+                }
+            });
+            td.addEventListener("drop", (event) => {
+                event.preventDefault();
+                if (play.dragged) {
+                    placeTile(play.dragged, r, c);
+                }
+            });
+        }
+    }
+    document.getElementById("board").replaceChildren(table);
+}
+
+function headerCell(className, content) {
+    const th = document.createElement("th");
+    th.className = className;
+    th.textContent = content;
+    return th;
+}
+
+function renderRack() {
+    const table = document.getElementById("rack");
+    table.replaceChildren();
+    const row = table.insertRow();
+    for (const tile of play.rack) {
+        if (play.placed.some((p) => p.tile === tile)) {
+            continue;
+        }
+        const td = row.insertCell();
+        const div = tileElement("tile", tile.letter === "?" ? "" : tile.letter, letterScore(tile.letter === "?" ? "?" : tile.letter));
+        if (tile === play.selected) {
+            div.classList.add("selected");
+        }
+        if (play.exchange && play.exchange.includes(tile)) {
+            div.classList.add("exchange");
+        }
+        div.draggable = myTurn() && play.exchange === null;
+        div.addEventListener("dragstart", () => { play.dragged = tile; });
+        div.addEventListener("click", () => clickRackTile(tile));
+        td.append(div);
+    }
+}
+
+function clickRackTile(tile) {
+    if (play.exchange !== null) {
+        const i = play.exchange.indexOf(tile);
+        if (i < 0) {
+            play.exchange.push(tile);
+        } else {
+            play.exchange.splice(i, 1);
+        }
+    } else {
+        play.selected = play.selected === tile ? null : tile;
+    }
+    render();
+}
+
+function clickSquare(row, column) {
+    const p = placedAt(row, column);
+    if (p) {
+        returnTile(p.tile);
+    } else if (play.selected) {
+        placeTile(play.selected, row, column);
+    }
+}
+
+function placeTile(tile, row, column) {
+    play.dragged = null;
+    if (!myTurn() || play.exchange !== null || play.view.board[row][column] || placedAt(row, column)) {
+        return;
+    }
+    const place = () => {
+        play.placed = play.placed.filter((p) => p.tile !== tile);
+        play.placed.push({ tile: tile, row: row, column: column });
+        play.selected = null;
+        changed();
+    };
+    if (tile.letter === "?" && !tile.blank) {
+        chooseBlank(tile, place);
+    } else {
+        place();
+    }
+}
+
+function returnTile(tile) {
+    play.dragged = null;
+    play.placed = play.placed.filter((p) => p.tile !== tile);
+    tile.blank = "";
+    changed();
+}
+
+// chooseBlank shows the letters of the language and calls done when the letter of the blank tile is chosen
+function chooseBlank(tile, done) {
+    const blank = document.getElementById("blank");
+    const letters = document.getElementById("blank-letters");
+    letters.replaceChildren();
+    for (const letter of Object.keys(play.view.letterScores).sort()) {
+        const button = document.createElement("button");
+        button.className = "blank-letter";
+        button.textContent = letter;
+        button.addEventListener("click", () => {
+            blank.classList.add("hidden");
+            tile.blank = letter;
+            done();
+        });
+        letters.append(button);
+    }
+    blank.classList.remove("hidden");
+}
+
+function recallTiles() {
+    for (const p of play.placed) {
+        p.tile.blank = "";
+    }
+    play.placed = [];
+    changed();
+}
+
+function shuffleRack() {
+    for (let i = play.rack.length - 1; i > 0; i--) {
+        const j = Math.floor(Math.random() * (i + 1));
+        [play.rack[i], play.rack[j]] = [play.rack[j], play.rack[i]];
+    }
+    render();
+}
+
+function exchangeTiles() {
+    if (play.exchange === null) {
+        recallTiles();
+        play.exchange = [];
+        showMessage(text("Select the tiles to exchange"));
+        render();
+        return;
+    }
+    const tiles = play.exchange.map((tile) => tile.letter).join("");
+    submit("/moves", { kind: "exchange", tiles: tiles });
+}
+
+function cancelExchange() {
+    play.exchange = null;
+    showMessage("");
+    render();
+}
+
+// wordAction returns the move of the placed tiles in orientation - a string telling why when there is none
+function wordAction(orientation) {
+    const horizontal = orientation === "horizontal";
+    const row = play.placed[0].row;
+    const column = play.placed[0].column;
+    if (play.placed.some((p) => horizontal ? p.row !== row : p.column !== column)) {
+        return text("The tiles must be placed in one row or column");
+    }
+    const at = (i) => horizontal ? letterAt(row, i) : letterAt(i, column);
+    const positions = play.placed.map((p) => horizontal ? p.column : p.row);
+    let start = Math.min(...positions);
+    let end = Math.max(...positions);
+    while (at(start - 1)) {
+        start--;
+    }
+    while (at(end + 1)) {
+        end++;
+    }
+    let word = "";
+    for (let i = start; i <= end; i++) {
+        if (!at(i)) {
+            return text("The placed tiles must form one word");
+        }
+        word += at(i);
+    }
+    return {
+        row: horizontal ? row : start,
+        column: horizontal ? start : column,
+        orientation: orientation,
+        word: word,
+    };
+}
+
+// actions returns the possible moves of the placed tiles - both orientations for a single tile
+function actions() {
+    if (play.placed.length === 1) {
+        const { row, column } = play.placed[0];
+        const across = letterAt(row, column - 1) || letterAt(row, column + 1);
+        const orientations = across ? ["horizontal", "vertical"] : ["vertical", "horizontal"];
+        return orientations.map(wordAction);
+    }
+    const first = play.placed[0];
+    const horizontal = play.placed.every((p) => p.row === first.row);
+    return [wordAction(horizontal ? "horizontal" : "vertical")];
+}
+
+// changed shows the placed tiles and the score and the words of their move from the validation api
+async function changed() {
+    play.action = null;
+    render();
+    if (play.placed.length === 0) {
+        showMessage(text("Place tiles on the board"));
+        return;
+    }
+    const preview = ++play.previews;
+    let message = "";
+    for (const action of actions()) {
+        if (typeof action === "string") {
+            message = message || action;
+            continue;
+        }
+        const result = await api("POST", "/validate", action);
+        if (preview !== play.previews) {
+            return;
+        }
+        if (!result.error) {
+            play.action = action;
+            const words = result.preview.words.map((w) => w.word).join(", ");
+            showMessage(format(text("%s gives %d points"), words, result.preview.score));
+            render();
+            return;
+        }
+        message = message || result.error.message;
+    }
+    showMessage(message);
+}
+
+function playMove() {
+    if (play.action) {
+        submit("/moves", play.action);
+    }
+}
+
+// submit posts the turn of the player - the response holds the game after the moves of the robot
+async function submit(path, body) {
+    play.busy = true;
+    render();
+    document.getElementById("status").replaceChildren(text("The robot is playing"));
+    const result = await api("POST", path, body);
+    play.busy = false;
+    if (result.error) {
+        showMessage(result.error.message);
+        render();
+        return;
+    }
+    showMessage("");
+    setView(result.game);
+    addMoves(result.moves || []);
+}
+
+function addMoves(moves) {
+    const list = document.getElementById("moves");
+    for (const move of moves) {
+        const li = document.createElement("li");
+        let s = `${move.name}: `;
+        switch (move.kind) {
+            case "move":
+                s += `${move.word} (${move.row || 0},${move.column || 0}) ${move.score}`;
+                break;
+            case "pass":
+                s += text("passed");
+                break;
+            case "exchange":
+                s += format(text("exchanged %d tiles"), move.exchanged);
+                break;
+            case "resign":
+                s += text("resigned");
+                break;
+        }
+        li.textContent = s;
+        list.append(li);
+    }
+}
+
+function showMessage(message) {
+    document.getElementById("preview").textContent = message;
+}
@@ -1,17 +1,14 @@
 #!/usr/bin/env bash
-export LC_COLLATE=C
+# download the Danish word lists and import them as corpus files (see wordfeud corpus import)
+set -e
+mkdir -p tmp
 curl -o data/corpus_dk.tsv https://ordregister.dk/files/cor1.02.tsv
-cut -f5 data/corpus_dk.tsv | sort -u >data/corpus_dk.txt
-wc -l data/*.txt
+go run . corpus import -to data/corpus_dk.txt data/corpus_dk.tsv:5
 #exit
 curl -o tmp/ddo.zip https://korpus.dsl.dk/download/ddo-fullform.zip
-unzip -o -d tmp tmp/ddo.zip
-mv tmp/ddo_fullforms_*.csv data/ddo.csv
-cut -f1 data/ddo.csv >data/ddo.txt
+go run . corpus import -member 'ddo_fullforms_*.csv' -to data/ddo.txt tmp/ddo.zip:1
 
 curl -o tmp/ods.zip https://korpus.dsl.dk/download/ods-fullform.zip
-unzip -o -d tmp tmp/ods.zip
-mv tmp/ods_fullforms_*.csv data/ods.csv
-cut -f1 data/ods.csv >data/ods.txt
+go run . corpus import -member 'ods_fullforms_*.csv' -to data/ods.txt tmp/ods.zip:1
 
 wc -l data/*.txt
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	. "wordfeud/context"
+	. "wordfeud/corpus"
+	. "wordfeud/dawg"
+	. "wordfeud/game"
+
+	"golang.org/x/text/message"
+)
+
+func wordCmd(options *GameOptions, args []string) *WordResult {
+	result := new(WordResult)
+	if len(args) == 0 {
+		fmt.Fprintln(result.errors(), "Please specify a word subcommand. (-Help for more info)")
+		return result.result()
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "find":
+		return wordFindCmd(options, args)
+	case "anagram":
+		return wordAnagramCmd(options, args)
+	case "hooks":
+		return wordHooksCmd(options, args)
+	case "info":
+		return wordInfoCmd(options, args)
+	case "add", "remove", "patch":
+		return wordOverlayCmd(options, cmd, args)
+	}
+	fmt.Fprintf(result.errors(), "unknown word subcommand '%q'.  (-Help for more info)\n", cmd)
+	return result.result()
+}
+
+func wordFindCmd(options *GameOptions, args []string) *WordResult {
+	var query WordQuery
+	var length int
+	var limit int
+	flag := flag.NewFlagSet("word find", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	StringVarFlag(flag, &query.Contains, []string{"contains"}, "", "letters which must all be in the words")
+	IntVarFlag(flag, &length, []string{"length"}, 0, "only find words of length letters")
+	IntVarFlag(flag, &query.MinLength, []string{"min"}, 0, "only find words of at least min letters")
+	IntVarFlag(flag, &query.MaxLength, []string{"max"}, 0, "only find words of at most max letters")
+	IntVarFlag(flag, &limit, []string{"limit"}, 0, "return at most limit words")
+	flag.Parse(args)
+	// the pattern may be followed by more flags
+	for flag.NArg() > 0 {
+		query.Pattern = flag.Arg(0)
+		flag.Parse(flag.Args()[1:])
+	}
+	if length > 0 {
+		query.MinLength = length
+		query.MaxLength = length
+	}
+	result := wordFind(options, query, limit)
+	if len(result.Err) <= 1 {
+		for _, w := range result.Words {
+			fmt.Fprintln(result.logger(), w)
+		}
+		fmt.Fprintf(result.logger(), "%d words found\n", len(result.Words))
+	}
+	return result.result()
+}
+
+// wordFind returns the words of the language dawg selected by query - at most limit words if limit is above 0
+func wordFind(options *GameOptions, query WordQuery, limit int) *WordResult {
+	result := new(WordResult)
+	dawg, err := languageDawg(options)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	words, err := FindWords(dawg, query)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	result.Words = make([]string, 0)
+	for w := range words {
+		if limit > 0 && len(result.Words) >= limit {
+			result.Truncated = true
+			break
+		}
+		result.Words = append(result.Words, w.String(dawg.Corpus()))
+		result.addMetadata(options, w)
+	}
+	return result.result()
+}
+
+func wordAnagramCmd(options *GameOptions, args []string) *WordResult {
+	result := new(WordResult)
+	var minLength int
+	flag := flag.NewFlagSet("word anagram", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	IntVarFlag(flag, &minLength, []string{"min"}, 0, "the minimum length of the words - 0 is the minimum word length of the language")
+	flag.Parse(args)
+	if flag.NArg() != 1 {
+		fmt.Fprintln(result.errors(), "Please specify the rack - e.g. \"ABCDE??\"")
+		return result.result()
+	}
+	dawg, err := languageDawg(options)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	corpus := dawg.Corpus()
+	rack, err := ParseRack(corpus, flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	if minLength <= 0 {
+		minLength = corpus.MinWordLength()
+	}
+	p := message.NewPrinter(options.Language)
+	for _, group := range FindAnagrams(dawg, rack, minLength) {
+		groupResult := AnagramGroupResult{Length: group.Length, Full: group.Full, Words: make([]AnagramWord, len(group.Anagrams))}
+		full := ""
+		if group.Full {
+			full = " (all tiles)"
+		}
+		p.Fprintf(result.logger(), "%d letters%s : %d words\n", group.Length, full, len(group.Anagrams))
+		for i, a := range group.Anagrams {
+			groupResult.Words[i] = AnagramWord{Word: a.Word.String(corpus), Score: a.Score}
+			result.addMetadata(options, a.Word)
+			p.Fprintf(result.logger(), "   %-15s %3d\n", groupResult.Words[i].Word, a.Score)
+		}
+		result.Anagrams = append(result.Anagrams, groupResult)
+	}
+	return result.result()
+}
+
+func wordHooksCmd(options *GameOptions, args []string) *WordResult {
+	result := new(WordResult)
+	flag := flag.NewFlagSet("word hooks", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	flag.Parse(args)
+	if flag.NArg() == 0 {
+		fmt.Fprintln(result.errors(), "Please specify one or more words")
+		return result.result()
+	}
+	dawg, err := languageDawg(options)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	corpus := dawg.Corpus()
+	for _, s := range flag.Args() {
+		word, err := corpus.ParseWord(s)
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			continue
+		}
+		front, back := dawg.Hooks(word)
+		hooks := WordHooks{
+			Word:       word.String(corpus),
+			Valid:      dawg.Match(word),
+			FrontHooks: make([]string, 0),
+			FrontWords: make([]string, 0),
+			BackHooks:  make([]string, 0),
+			BackWords:  make([]string, 0),
+		}
+		for l := corpus.FirstLetter(); l <= corpus.LastLetter(); l++ {
+			if front.Test(l) {
+				hooks.FrontHooks = append(hooks.FrontHooks, l.String(corpus))
+				hooks.FrontWords = append(hooks.FrontWords, slices.Concat(Word{l}, word).String(corpus))
+			}
+			if back.Test(l) {
+				hooks.BackHooks = append(hooks.BackHooks, l.String(corpus))
+				hooks.BackWords = append(hooks.BackWords, slices.Concat(word, Word{l}).String(corpus))
+			}
+		}
+		valid := ""
+		if !hooks.Valid {
+			valid = " (not a word)"
+		}
+		fmt.Fprintf(result.logger(), "%s%s\n", hooks.Word, valid)
+		fmt.Fprintf(result.logger(), "   front hooks %s : %s\n", strings.Join(hooks.FrontHooks, ""), strings.Join(hooks.FrontWords, " "))
+		fmt.Fprintf(result.logger(), "   back hooks  %s : %s\n", strings.Join(hooks.BackHooks, ""), strings.Join(hooks.BackWords, " "))
+		result.Hooks = append(result.Hooks, hooks)
+	}
+	return result.result()
+}
+
+// wordInfoCmd returns the metadata of the words
+func wordInfoCmd(options *GameOptions, args []string) *WordResult {
+	flag := flag.NewFlagSet("word info", flag.ExitOnError)
+	registerGlobalFlags(flag)
+	flag.Parse(args)
+	if flag.NArg() == 0 {
+		result := new(WordResult)
+		fmt.Fprintln(result.errors(), "Please specify one or more words")
+		return result.result()
+	}
+	result := wordInfo(options, flag.Args())
+	for _, w := range result.Words {
+		metadata, found := result.Metadata[w]
+		if !found {
+			fmt.Fprintf(result.logger(), "%s : no information\n", w)
+			continue
+		}
+		fmt.Fprintf(result.logger(), "%s\n", w)
+		fmt.Fprintf(result.logger(), "   lemma          : %s\n", metadata.Lemma)
+		fmt.Fprintf(result.logger(), "   part of speech : %s\n", metadata.POS)
+		fmt.Fprintf(result.logger(), "   frequency rank : %d\n", metadata.FrequencyRank)
+		fmt.Fprintf(result.logger(), "   gloss          : %s\n", metadata.Gloss)
+	}
+	return result.result()
+}
+
+// wordInfo returns the words of the language dictionary among words and their metadata
+func wordInfo(options *GameOptions, words []string) *WordResult {
+	result := new(WordResult)
+	dawg, err := languageDawg(options)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	corpus := dawg.Corpus()
+	result.Words = make([]string, 0, len(words))
+	for _, s := range words {
+		word, err := corpus.ParseWord(s)
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			continue
+		}
+		if !dawg.Match(word) {
+			fmt.Fprintf(result.errors(), "\"%s\" is not a word of the dictionary\n", word.String(corpus))
+			continue
+		}
+		result.Words = append(result.Words, word.String(corpus))
+		result.addMetadata(options, word)
+	}
+	return result.result()
+}
+
+// addMetadata adds the metadata of word (if any) in the language dictionary to the result
+func (result *WordResult) addMetadata(options *GameOptions, word Word) {
+	content, err := SharedDictionaryContent(options.Language, options.Dictionary)
+	if err != nil {
+		return
+	}
+	if metadata, found := content.Metadata(word); found {
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]WordMetadata)
+		}
+		result.Metadata[word.String(content.Corpus())] = metadata
+	}
+}
+
+// wordOverlayCmd adds words to or removes words from the dictionary by updating the overlay file.
+// The patch subcommand applies the words added and removed in patch files (see corpus diff).
+func wordOverlayCmd(options *GameOptions, cmd string, args []string) *WordResult {
+	result := new(WordResult)
+	flag := flag.NewFlagSet("word "+cmd, flag.ExitOnError)
+	registerGlobalFlags(flag)
+	flag.Parse(args)
+	if flag.NArg() == 0 {
+		fmt.Fprintln(result.errors(), "Please specify one or more words or patch files")
+		return result.result()
+	}
+	content, err := SharedDictionaryContent(options.Language, options.Dictionary)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	if content.FileName() == "" {
+		fmt.Fprintf(result.errors(), "the dictionary of %s has no corpus file to keep an overlay next to\n", options.Language.String())
+		return result.result()
+	}
+	dawg, err := SharedDawg(content, options.Options)
+	if err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	// the words are added to a copy - the shared dawg is used by the running games
+	var overlayDawg OverlayDawg
+	if shared, ok := dawg.(OverlayDawg); ok {
+		overlayDawg = NewOverlayDawg(shared.Base(), shared.Overlay())
+	} else {
+		overlayDawg = NewOverlayDawg(dawg, Overlay{})
+	}
+	corpus := dawg.Corpus()
+	for _, s := range flag.Args() {
+		var patch Overlay
+		if cmd == "patch" {
+			patch, err = ReadOverlayFile(s, corpus)
+		} else {
+			var word Word
+			word, err = corpus.ParseWord(s)
+			if cmd == "add" {
+				patch.Added = Words{word}
+			} else {
+				patch.Removed = Words{word}
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(result.errors(), err.Error())
+			return result.result()
+		}
+		for _, word := range patch.Added {
+			if err = overlayDawg.Add(word); err != nil {
+				fmt.Fprintln(result.errors(), err.Error())
+				return result.result()
+			}
+			result.Words = append(result.Words, word.String(corpus))
+		}
+		for _, word := range patch.Removed {
+			if err = overlayDawg.Remove(word); err != nil {
+				fmt.Fprintln(result.errors(), err.Error())
+				return result.result()
+			}
+			result.Words = append(result.Words, word.String(corpus))
+		}
+	}
+	overlay := overlayDawg.Overlay()
+	overlayFileName := OverlayFileName(content.FileName())
+	if err = WriteOverlayFile(overlayFileName, overlay, corpus); err != nil {
+		fmt.Fprintln(result.errors(), err.Error())
+		return result.result()
+	}
+	fmt.Fprintf(result.logger(), "%s : %d words added and %d words removed\n", overlayFileName, len(overlay.Added), len(overlay.Removed))
+	return result.result()
+}
+
+func languageDawg(options *GameOptions) (Dawg, error) {
+	content, err := SharedDictionaryContent(options.Language, options.Dictionary)
+	if err != nil {
+		return nil, err
+	}
+	return SharedDawg(content, options.Options)
+}
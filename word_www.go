@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	. "wordfeud/context"
+	. "wordfeud/dawg"
+)
+
+const wordFindDefaultLimit = 1000
+
+// wordFindWWW answers /scrabble/word/find?p=pattern&c=letters&min=nn&max=nn&limit=nn with a json WordResult
+// - the limit is 1 .. wordFindDefaultLimit
+func wordFindWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	wordQuery := WordQuery{
+		Pattern:  query.Get("p"),
+		Contains: query.Get("c"),
+	}
+	wordQuery.MinLength, _ = strconv.Atoi(query.Get("min"))
+	wordQuery.MaxLength, _ = strconv.Atoi(query.Get("max"))
+	if length, err := strconv.Atoi(query.Get("len")); err == nil {
+		wordQuery.MinLength = length
+		wordQuery.MaxLength = length
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit > wordFindDefaultLimit {
+		limit = wordFindDefaultLimit
+	}
+	limit = max(limit, 1) // a limit of 0 is no limit to wordFind
+
+	result := wordFind(options, wordQuery, limit)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if len(result.Err) > 1 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// wordInfoWWW answers /scrabble/word/info?w=word&w=word with a json WordResult holding the metadata of the words
+func wordInfoWWW(server *Server, options *GameOptions, w http.ResponseWriter, req *http.Request) {
+	result := wordInfo(options, req.URL.Query()["w"])
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if len(result.Err) > 1 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(result)
+}
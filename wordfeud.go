@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"math/rand"
 	"os"
@@ -32,16 +34,110 @@ import (
 const usage = `
 	wordfeud {options} serve {-port=pppp}
 		start http server on port pppp (default is 6789)
- 
-	wordfeud {options} corpus 
-		return corpus information
+		the json api of the server plays games of people and bots:
+			POST /api/v1/games				create a game - {"language":"da","ruleset":"...","seed":nn,
+											"players":[{"name":"ann"},{"bot":"casual"}]} - returns the game id
+											and a token for each human player - a game has at least one
+											human player and is removed after a day without requests
+			GET  /api/v1/games/{id}			the board, the bag size, the scores and the rack of the caller
+			GET  /api/v1/games/{id}/moves	the moves of the game
+			POST /api/v1/games/{id}/moves	{"row":7,"column":7,"orientation":"horizontal","word":"ORD"} or
+											{"kind":"exchange","tiles":"AB?"} or {"kind":"pass"}
+			POST /api/v1/games/{id}/validate	the score and the words of a move without playing it - as for a move
+											- the words and the moves hold the metadata of the corpus when it has one
+			POST /api/v1/games/{id}/resign	the caller resigns
+		the caller sends its token as "Authorization: Bearer token" or ?token=token. A failed request
+		answers {"error":{"status":nnn,"code":"...","message":"..."}} with the http status
+		a user signs in (or creates a user) on /scrabble/signin and plays against a robot of a chosen
+		level on /scrabble/play - tiles are placed by drag and drop or a click on a rack tile and a square
+		and the score and the words of the placed tiles are shown while placing. The users are kept in
+		the store .store/users
 
-	wordfeud {options} dawg 
+	wordfeud {options} corpus {-json}
+		return corpus information and the number of words removed by each filter rule
+		of the language (alphabet, length, exclude lists and patterns), the number of 2 and 3
+		letter words and of 7 and 8 letter (bingo) words, the word length histogram, the letter
+		frequencies and the most frequent bigrams. -json writes the statistics as json
+		the http server answers /scrabble/corpus/stat?dict=spec with the statistics as json
+
+	wordfeud {options} corpus import {-sep=x} {-member=pattern} {-pos=nn} {-lemma=nn} {-freq=nn} {-gloss=nn} {-to=file} source...
+		import the words of the source files into the corpus file (default "data/corpus_xx.txt")
+		a source is "file" or "file:nn" where nn is the column holding the word in a file of
+		columns separated by -sep (default tab). A zip archive is read file by file - only the
+		files matching -member when given. The words are normalized (Unicode NFC), checked
+		against the alphabet, deduplicated and sorted in byte order and the lines which
+		are not words are written to "data/corpus_xx.rejected". With -pos=nn the words with a part
+		of speech (in column nn) excluded by the filter rules of the language are rejected
+		with -lemma, -freq and/or -gloss the lemma, the frequency and the definition of the words
+		(in the columns nn) are written to the metadata file "data/corpus_xx.meta" - the words are
+		ranked by frequency
+
+	wordfeud {options} corpus diff {-games=dir} {-patch=file} old new
+		compare the corpus files old and new and report the words added and removed by length
+		and the removed words played in the json game files of dir (default the -out directory)
+		the changes are written to a patch file (default new with extension ".patch") in the
+		format of the overlay file - apply it with word patch
+
+	wordfeud {options} corpus tiles {-bag=nn} {-blanks=nn} {-to=file}
+		propose the tile counts for a bag of nn tiles (default 100) and the letter values from
+		the words of at most 8 letters - the count from the frequency of the letter and the value
+		from the share of words and two letter words holding it - compared with the tiles of the
+		language definition. The language definition with the proposed tiles is written to file
+		(default printed) and can be placed in "data/languages"
+
+	wordfeud {options} dawg {-compile} {-fold} {-list {-prefix=xx} {-length=nn}}
     	return dawg information
+		-compile	build the dawg and write the compiled dawg file "data/corpus_xx.dawg"
+					which is loaded instead of building the dawg when the corpus is unchanged
+		-fold		fold the words added and removed in the overlay file "data/corpus_xx.overlay"
+					into the corpus file, write a fresh compiled dawg file and remove the overlay file
+		-list		list the words of the dawg in corpus collation order
+		-prefix=xx	only list words starting with xx
+		-length=nn	only list words of nn letters
+
+	wordfeud {options} word find {-contains=xx} {-length=nn} {-min=nn} {-max=nn} {-limit=nn} pattern
+		find the words matching pattern where
+			?		is any single letter
+			*		is any number of letters
+			[xyz]	is one of the letters x, y and z
+			[^xyz]	is any letter but x, y and z
+		-contains=xx	the words must contain all the letters xx
+		-length=nn		only words of nn letters (-min and -max limit the length)
+		-limit=nn		return at most nn words
+		the http server answers /scrabble/word/find?p=pattern&c=xx&len=nn&min=nn&max=nn&limit=nn with json
+		- at most 1000 words
+
+	wordfeud {options} word anagram {-min=nn} rack
+		find the words of at least nn letters which can be made from the tiles of rack
+		- e.g. "ABCDE??" where ? is a joker - grouped by length and scored with the letter values
+		- nn is by default the minimum word length of the language
+		the words found by find and anagram include the metadata of the corpus when it has one
+
+	wordfeud {options} word info word...
+		show the lemma, part of speech, frequency rank and definition of each word from the
+		metadata file of the corpus
+		the http server answers /scrabble/word/info?w=word with json
+
+	wordfeud {options} word hooks word...
+		show the letters which can be put in front of or after each word to form another word
+
+	wordfeud {options} word add word...
+	wordfeud {options} word remove word...
+		add words to or remove words from the dictionary without rebuilding the dawg
+		the changes are kept in the overlay file "data/corpus_xx.overlay" (see dawg -fold)
+
+	wordfeud {options} word patch file...
+		add and remove the words of patch files (see corpus diff) to the overlay file
 
 	wordfeud {options} game 
     	return game information
 
+	wordfeud {options} game check {-strict} file...
+		compare the dictionary fingerprint (the checksum of the words) and the ruleset (the tiles,
+		the board and the rack size) in the header of the text, json or html game files with
+		those of the dictionary and the language the game would be played with now. A game
+		file of another dictionary or ruleset gives a warning - or with -strict an error
+
 	wordfeud {options} autoplay 
     	play game automatically 
 
@@ -66,8 +162,33 @@ const usage = `
 								"debug": text file with debug info
 								"json": json file
 								"html": json file
+		-language=xx		the language of the game (default "da"). The languages are defined in
+							JSON files - built in or in the directory "data/languages"
+		-lexicon=xxxx		the lexicon structure used for move generation
+							"dawg" (default) or "gaddag" which grows words in both
+							directions from each anchor
+		-level=xxxx		the levels of the bot players separated by comma - e.g.
+							"beginner,expert" - the last level is used for the remaining
+							players. A level is one of
+								"beginner": knows the 5,000 most frequent words and plays an average move
+								"casual": knows the 20,000 most frequent words and plays a fair move
+								"strong": knows all words and plays one of the best moves
+								"expert": knows all words and plays the best move (default)
+							the vocabulary is only limited when the corpus has a metadata
+							file with frequency ranks (see corpus import)
+		-dictionary=xxxx	the word lists (sources) of the language combined into the
+							dictionary from left to right with + (union), & (intersection)
+							and - (minus) - e.g. "ddo + ods - proper". The sources are
+							named in the "sources" of the language definition
 						
 
+	corpus files:
+		a corpus file - e.g. "data/corpus_dk.txt" - and an import source may be gzip compressed
+		"data/corpus_dk.txt.gz" is read when "data/corpus_dk.txt" is not there. Built with
+		"go generate -tags embedcorpus" and "go build -tags embedcorpus" the binary holds the
+		compressed corpus files, the compiled dawgs of data and the templates so autoplay and
+		serve run in any directory
+
 	abbreviated options:
 		-h		-help
 		-v		-verbose
@@ -96,6 +217,8 @@ func main() {
 	var languageSpec string
 	var fileFormatSpec string
 	var ranSeedSpec string
+	var lexiconSpec string
+	var levelSpec string
 	options.Out = os.Stdout
 	options.Language = language.Danish
 	flag.Usage = func() { fmt.Print(usage) }
@@ -109,6 +232,9 @@ func main() {
 	StringVarFlag(flag.CommandLine, &options.Name, []string{"name", "n"}, "", "name of game files ")
 	StringVarFlag(flag.CommandLine, &options.Directory, []string{"out", "o"}, "", "the name of the file or directory to hold game result")
 	StringVarFlag(flag.CommandLine, &fileFormatSpec, []string{"format", "f"}, "", "the format of output file")
+	StringVarFlag(flag.CommandLine, &lexiconSpec, []string{"lexicon"}, "", "the lexicon structure used for move generation")
+	StringVarFlag(flag.CommandLine, &levelSpec, []string{"level"}, "", "the levels of the bot players")
+	StringVarFlag(flag.CommandLine, &options.Dictionary, []string{"dictionary", "dict"}, "", "the word lists combined into the dictionary")
 
 	flag.Parse()
 	args := flag.Args()
@@ -121,6 +247,10 @@ func main() {
 		}
 		return
 	}
+	if err := LoadLanguages(LanguageDirectory); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return
+	}
 	if len(languageSpec) > 0 {
 		tag, err := language.Default.Parse(languageSpec)
 		if err != nil {
@@ -128,12 +258,29 @@ func main() {
 			return
 		}
 		if !SupportedLanguage(tag) {
-			fmt.Fprintf(os.Stderr, "unsupported language \"%s\"\n", languageSpec)
+			supported := make([]string, 0)
+			for _, tag := range SupportedLanguages() {
+				supported = append(supported, fmt.Sprintf("%s (%s)", tag.String(), GetLanguageName(tag, language.English)))
+			}
+			fmt.Fprintf(os.Stderr, "unsupported language \"%s\" - supported languages are %s\n", languageSpec, strings.Join(supported, ", "))
 			return
 		}
 		options.Language = tag
 	}
 
+	lexicon, err := ParseLexicon(lexiconSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return
+	}
+	options.Lexicon = lexicon
+
+	options.BotLevels, err = ParseBotLevels(levelSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return
+	}
+
 	if len(ranSeedSpec) > 0 {
 		ranSeedSpec = strings.ReplaceAll(ranSeedSpec, ",", "")
 		ranSeedSpec = strings.ReplaceAll(ranSeedSpec, ".", "")
@@ -180,8 +327,8 @@ func main() {
 		options.Print()
 	}
 
-	err := godotenv.Load()
-	if err != nil {
+	err = godotenv.Load()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		log.Fatal("Error loading .env file")
 	}
 
@@ -194,6 +341,9 @@ func main() {
 	case "dawg":
 		result := dawgCmd(&options, args)
 		fmt.Print(strings.Join(result.Log, "\n"))
+	case "word":
+		result := wordCmd(&options, args)
+		fmt.Print(strings.Join(result.Log, "\n"))
 	case "game":
 		result := gameCmd(&options, args)
 		fmt.Print(strings.Join(result.Log, "\n"))